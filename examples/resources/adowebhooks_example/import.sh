@@ -0,0 +1,8 @@
+# Subscriptions can be imported by ID
+terraform import adoservicehooks_subscription.example 5e2b2a5f-1c3d-4e5f-8a9b-0c1d2e3f4a5b
+
+# or by <project>/<repository>/<event type>/<url>, leave the repository empty for subscriptions without repository filter
+terraform import adoservicehooks_subscription.example "My Project/my-repository/git.push/https://receiver.example.com/hook"
+
+# Prefix either form with <organization>: to import from another organization than the provider's
+terraform import adoservicehooks_subscription.example other-organization:5e2b2a5f-1c3d-4e5f-8a9b-0c1d2e3f4a5b
@@ -0,0 +1,166 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"sort"
+	"strings"
+)
+
+// knownEventTypes lists the event types of the service hook publishers known to the provider.
+// Organizations can have additional publishers installed by extensions, so the catalog is used to
+// catch typos rather than to reject everything it does not know.
+var knownEventTypes = map[string][]string{
+	"tfs": {
+		"build.complete",
+		"git.pullrequest.created",
+		"git.pullrequest.merged",
+		"git.pullrequest.updated",
+		"git.push",
+		"git.repo.created",
+		"git.repo.deleted",
+		"git.repo.forked",
+		"git.repo.renamed",
+		"git.repo.statuschanged",
+		"ms.vss-code.git-pullrequest-comment-event",
+		"tfvc.checkin",
+		"workitem.commented",
+		"workitem.created",
+		"workitem.deleted",
+		"workitem.restored",
+		"workitem.updated",
+	},
+	"rm": {
+		"ms.vss-release.deployment-approval-completed-event",
+		"ms.vss-release.deployment-approval-pending-event",
+		"ms.vss-release.deployment-completed-event",
+		"ms.vss-release.deployment-started-event",
+		"ms.vss-release.release-abandoned-event",
+		"ms.vss-release.release-created-event",
+	},
+	"pipelines": {
+		"ms.vss-pipelinechecks-events.approval-completed",
+		"ms.vss-pipelinechecks-events.approval-pending",
+		"ms.vss-pipelines.job-state-changed-event",
+		"ms.vss-pipelines.run-state-changed-event",
+		"ms.vss-pipelines.stage-state-changed-event",
+	},
+}
+
+// knownConsumerActions lists the actions of the service hook consumers known to the provider.
+var knownConsumerActions = map[string][]string{
+	"appVeyor":          {"webHook"},
+	"azureServiceBus":   {"serviceBusNotificationHubSend", "serviceBusQueueSend", "serviceBusTopicSend"},
+	"azureStorageQueue": {"enqueue"},
+	"bamboo":            {"queueBuild"},
+	"datadog":           {"postEventInDatadog"},
+	"grafana":           {"addAnnotation"},
+	"jenkins":           {"triggerGenericBuild", "triggerGitBuild"},
+	"slack":             {"postMessageToChannel"},
+	"trello":            {"createCard", "createList"},
+	"webHooks":          {"httpRequest"},
+	"zendesk":           {"createPrivateComment"},
+}
+
+// resourceDetailsValues are the accepted values of the webhook resource detail inputs.
+var resourceDetailsValues = []string{"all", "minimal", "none"}
+
+// messageValues are the accepted values of the webhook message inputs.
+var messageValues = []string{"all", "html", "markdown", "none", "text"}
+
+func knownPublisherIds() []string {
+	return sortedKeys(knownEventTypes)
+}
+
+func knownConsumerIds() []string {
+	return sortedKeys(knownConsumerActions)
+}
+
+func allKnownEventTypes() []string {
+	return flattenValues(knownEventTypes)
+}
+
+func allKnownConsumerActions() []string {
+	return flattenValues(knownConsumerActions)
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func flattenValues(m map[string][]string) []string {
+	seen := map[string]struct{}{}
+	var values []string
+	for _, list := range m {
+		for _, value := range list {
+			if _, ok := seen[value]; !ok {
+				seen[value] = struct{}{}
+				values = append(values, value)
+			}
+		}
+	}
+	sort.Strings(values)
+	return values
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// suggest returns the candidate closest to value if it is close enough to be a likely typo,
+// or an empty string otherwise. Matching ignores case.
+func suggest(value string, candidates []string) string {
+	best := ""
+	bestDistance := 0
+	for _, candidate := range candidates {
+		distance := levenshtein(strings.ToLower(value), strings.ToLower(candidate))
+		if best == "" || distance < bestDistance {
+			best = candidate
+			bestDistance = distance
+		}
+	}
+
+	// Allow roughly one typo per four characters, but at least two
+	maxDistance := len(value) / 4
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+
+	if best == "" || bestDistance > maxDistance {
+		return ""
+	}
+	return best
+}
+
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
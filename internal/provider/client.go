@@ -6,25 +6,49 @@ package provider
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"time"
 )
 
+// NotFoundError is returned when the requested Azure DevOps object does not exist (anymore).
+type NotFoundError struct {
+	Kind string
+	ID   string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s %q not found", e.Kind, e.ID)
+}
+
+// IsNotFound reports whether err is or wraps a NotFoundError.
+func IsNotFound(err error) bool {
+	var notFound *NotFoundError
+	return errors.As(err, &notFound)
+}
+
 type Client struct {
-	HTTPClient   *http.Client
-	Organization string
-	Pat          string
-	BaseURL      string
+	HTTPClient      *http.Client
+	Organization    string
+	Pat             string
+	BaseURL         string
+	IdentityBaseURL string
+
+	// metadata is shared with the clients derived by WithOrganization
+	metadata *metadataCache
 }
 
 func NewClient(organization, pat *string) (*Client, error) {
 	c := &Client{
 		HTTPClient: &http.Client{Timeout: 10 * time.Second},
 		// Assuming a default URL for Azure DevOps organization
-		BaseURL:      "https://dev.azure.com/",
-		Organization: "",
-		Pat:          "",
+		BaseURL:         "https://dev.azure.com/",
+		IdentityBaseURL: "https://vssps.dev.azure.com/",
+		Organization:    "",
+		Pat:             "",
+		metadata:        newMetadataCache(),
 	}
 
 	if organization != nil {
@@ -43,6 +67,19 @@ func NewClient(organization, pat *string) (*Client, error) {
 	return c, nil
 }
 
+// WithOrganization returns a client for the given organization which shares the
+// credentials and HTTP client of c. An empty organization returns c unchanged.
+func (c *Client) WithOrganization(organization string) *Client {
+	if organization == "" || organization == c.Organization {
+		return c
+	}
+
+	orgClient := *c
+	orgClient.Organization = organization
+
+	return &orgClient
+}
+
 func (c *Client) createRawRequest(method, url string, body interface{}) (*http.Request, error) {
 	var reqBody []byte
 	var err error
@@ -70,7 +107,7 @@ func (c *Client) createRawRequest(method, url string, body interface{}) (*http.R
 }
 
 func (c *Client) GetProjectGuid(project string) (*IdResponse, error) {
-	req, err := c.createRawRequest("GET", c.BaseURL+c.Organization+"/_apis/projects/"+project+"?api-version=7.0", nil)
+	req, err := c.createRawRequest("GET", c.BaseURL+c.Organization+"/_apis/projects/"+url.PathEscape(project)+"?api-version=7.0", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -81,6 +118,10 @@ func (c *Client) GetProjectGuid(project string) (*IdResponse, error) {
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, &NotFoundError{Kind: "project", ID: project}
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("failed to get id, status code: %d", resp.StatusCode)
 	}
@@ -94,7 +135,7 @@ func (c *Client) GetProjectGuid(project string) (*IdResponse, error) {
 }
 
 func (c *Client) GetRepositoryGuid(project, repository string) (*IdResponse, error) {
-	req, err := c.createRawRequest("GET", c.BaseURL+c.Organization+"/"+project+"/_apis/git/repositories/"+repository+"?api-version=7.0", nil)
+	req, err := c.createRawRequest("GET", c.BaseURL+c.Organization+"/"+url.PathEscape(project)+"/_apis/git/repositories/"+url.PathEscape(repository)+"?api-version=7.0", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -105,6 +146,10 @@ func (c *Client) GetRepositoryGuid(project, repository string) (*IdResponse, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, &NotFoundError{Kind: "repository", ID: repository}
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("failed to get id, status code: %d", resp.StatusCode)
 	}
@@ -129,6 +174,10 @@ func (c *Client) GetWebhook(webhookID string) (*WebhookSubscription, error) {
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, &NotFoundError{Kind: "webhook", ID: webhookID}
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("failed to get webhook, status code: %d", resp.StatusCode)
 	}
@@ -189,6 +238,10 @@ func (c *Client) DeleteWebhook(webhookID string) error {
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return &NotFoundError{Kind: "webhook", ID: webhookID}
+	}
+
 	if resp.StatusCode != http.StatusNoContent {
 		return fmt.Errorf("failed to delete webhook, status code: %d", resp.StatusCode)
 	}
@@ -196,6 +249,192 @@ func (c *Client) DeleteWebhook(webhookID string) error {
 	return nil
 }
 
+// ListWebhooks returns the subscriptions of the organization, filtered by event type unless empty.
+func (c *Client) ListWebhooks(eventType string) ([]WebhookSubscription, error) {
+	requestURL := c.BaseURL + c.Organization + "/_apis/hooks/subscriptions?api-version=7.0"
+	if eventType != "" {
+		requestURL += "&eventType=" + url.QueryEscape(eventType)
+	}
+
+	var list struct {
+		Value []WebhookSubscription `json:"value"`
+	}
+	if err := c.getJSON(requestURL, &list); err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	return list.Value, nil
+}
+
+// GetEventTypes returns the event types of a publisher including their input descriptors. The
+// result is cached per organization for the lifetime of the provider.
+func (c *Client) GetEventTypes(publisherId string) ([]EventTypeDescriptor, error) {
+	key := c.Organization + "/" + publisherId
+	if eventTypes, ok := c.metadata.eventTypes(key); ok {
+		return eventTypes, nil
+	}
+
+	var list struct {
+		Value []EventTypeDescriptor `json:"value"`
+	}
+	if err := c.getJSON(c.BaseURL+c.Organization+"/_apis/hooks/publishers/"+url.PathEscape(publisherId)+"/eventTypes?api-version=7.0", &list); err != nil {
+		return nil, fmt.Errorf("failed to get event types of publisher %q: %w", publisherId, err)
+	}
+
+	c.metadata.setEventTypes(key, list.Value)
+	return list.Value, nil
+}
+
+// GetConsumerActions returns the actions of a consumer including their input descriptors. The
+// result is cached per organization for the lifetime of the provider.
+func (c *Client) GetConsumerActions(consumerId string) ([]ConsumerActionDescriptor, error) {
+	key := c.Organization + "/" + consumerId
+	if actions, ok := c.metadata.consumerActions(key); ok {
+		return actions, nil
+	}
+
+	var list struct {
+		Value []ConsumerActionDescriptor `json:"value"`
+	}
+	if err := c.getJSON(c.BaseURL+c.Organization+"/_apis/hooks/consumers/"+url.PathEscape(consumerId)+"/actions?api-version=7.0", &list); err != nil {
+		return nil, fmt.Errorf("failed to get actions of consumer %q: %w", consumerId, err)
+	}
+
+	c.metadata.setConsumerActions(key, list.Value)
+	return list.Value, nil
+}
+
+// getJSON sends a GET request and decodes the JSON response into out.
+func (c *Client) getJSON(requestURL string, out interface{}) error {
+	req, err := c.createRawRequest("GET", requestURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return &NotFoundError{Kind: "resource", ID: requestURL}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return nil
+}
+
+// SendTestNotification sends a sample event through the consumer of a subscription. The returned
+// notification is usually still pending, see GetNotification.
+func (c *Client) SendTestNotification(notification *Notification) (*Notification, error) {
+	var sent Notification
+	if err := c.postJSON(c.BaseURL+c.Organization+"/_apis/hooks/testNotifications?api-version=7.0", notification, &sent); err != nil {
+		return nil, fmt.Errorf("failed to send test notification: %w", err)
+	}
+
+	return &sent, nil
+}
+
+// GetNotification returns a notification of a subscription including its delivery result.
+func (c *Client) GetNotification(subscriptionId string, notificationId int64) (*Notification, error) {
+	var notification Notification
+	requestURL := fmt.Sprintf("%s%s/_apis/hooks/subscriptions/%s/notifications/%d?api-version=7.0", c.BaseURL, c.Organization, url.PathEscape(subscriptionId), notificationId)
+	if err := c.getJSON(requestURL, &notification); err != nil {
+		return nil, fmt.Errorf("failed to get notification %d: %w", notificationId, err)
+	}
+
+	return &notification, nil
+}
+
+// postJSON sends body as JSON in a POST request and decodes the JSON response into out.
+func (c *Client) postJSON(requestURL string, body, out interface{}) error {
+	req, err := c.createRawRequest("POST", requestURL, body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 201 {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return nil
+}
+
+// GetPersonalAccessTokens lists the active personal access tokens of the authenticated identity
+// using the PAT lifecycle API. The API is not available to every token, callers should treat
+// errors as "unknown" rather than failing.
+func (c *Client) GetPersonalAccessTokens() ([]PersonalAccessToken, error) {
+	var tokens []PersonalAccessToken
+	continuationToken := ""
+
+	for {
+		requestURL := c.IdentityBaseURL + c.Organization + "/_apis/tokens/pats?displayFilterOption=active&api-version=7.1-preview.1"
+		if continuationToken != "" {
+			requestURL += "&continuationToken=" + url.QueryEscape(continuationToken)
+		}
+
+		req, err := c.createRawRequest("GET", requestURL, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to send request: %w", err)
+		}
+
+		// Azure DevOps answers unauthorized calls with a 203 sign-in page, so anything but 200 is a failure
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to list personal access tokens, status code: %d", resp.StatusCode)
+		}
+
+		var page personalAccessTokenPage
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+
+		tokens = append(tokens, page.PatTokens...)
+		if page.ContinuationToken == "" {
+			return tokens, nil
+		}
+		continuationToken = page.ContinuationToken
+	}
+}
+
 type IdResponse struct {
-	ID string `json:"id"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// PersonalAccessToken describes a personal access token as returned by the PAT lifecycle API.
+type PersonalAccessToken struct {
+	AuthorizationId string    `json:"authorizationId"`
+	DisplayName     string    `json:"displayName"`
+	ValidTo         time.Time `json:"validTo"`
+}
+
+type personalAccessTokenPage struct {
+	ContinuationToken string                `json:"continuationToken"`
+	PatTokens         []PersonalAccessToken `json:"patTokens"`
 }
@@ -4,6 +4,8 @@
 package provider
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 )
@@ -70,3 +72,46 @@ func TestClient(t *testing.T) {
 	}
 	// Your test code here...
 }
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	org := "org"
+	pat := "pat"
+	client, err := NewClient(&org, &pat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.BaseURL = server.URL + "/"
+	client.IdentityBaseURL = server.URL + "/"
+
+	return client
+}
+
+func TestClientNotFound(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, err := client.GetWebhook("missing")
+	if !IsNotFound(err) {
+		t.Errorf("expected not found error from GetWebhook, got %v", err)
+	}
+
+	err = client.DeleteWebhook("missing")
+	if !IsNotFound(err) {
+		t.Errorf("expected not found error from DeleteWebhook, got %v", err)
+	}
+}
+
+func TestClientServerError(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	_, err := client.GetWebhook("broken")
+	if err == nil || IsNotFound(err) {
+		t.Errorf("expected generic error from GetWebhook, got %v", err)
+	}
+}
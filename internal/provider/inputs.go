@@ -0,0 +1,221 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// marshalInputs serializes the typed inputs into a JSON object and adds the additional free-form
+// inputs to it. Typed inputs win over additional inputs with the same id.
+func marshalInputs(typed interface{}, additional map[string]string) ([]byte, error) {
+	data, err := json.Marshal(typed)
+	if err != nil || len(additional) == 0 {
+		return data, err
+	}
+
+	merged := map[string]interface{}{}
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return nil, err
+	}
+
+	for id, value := range additional {
+		if _, ok := merged[id]; !ok {
+			merged[id] = value
+		}
+	}
+
+	return json.Marshal(merged)
+}
+
+// unmarshalAdditionalInputs returns the inputs of the JSON object which are not covered by a field
+// of typed. Inputs which are not strings are returned in their JSON representation.
+func unmarshalAdditionalInputs(data []byte, typed interface{}) (map[string]string, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	known := jsonFieldNames(typed)
+	additional := map[string]string{}
+	for id, value := range raw {
+		if _, ok := known[id]; ok || string(value) == "null" {
+			continue
+		}
+
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			s = string(value)
+		}
+		additional[id] = s
+	}
+
+	if len(additional) == 0 {
+		return nil, nil
+	}
+	return additional, nil
+}
+
+// jsonFieldNames returns the JSON names of the fields of a struct.
+func jsonFieldNames(v interface{}) map[string]struct{} {
+	names := map[string]struct{}{}
+	t := reflect.TypeOf(v)
+	for i := 0; i < t.NumField(); i++ {
+		if name := jsonFieldName(t.Field(i)); name != "" {
+			names[name] = struct{}{}
+		}
+	}
+	return names
+}
+
+// typedInputAttributes maps the JSON input ids of jsonModel to the attribute names of tfModel by
+// matching the Go field names of both structs.
+func typedInputAttributes(tfModel, jsonModel interface{}) map[string]string {
+	attributes := map[string]string{}
+	tfType := reflect.TypeOf(tfModel)
+	jsonType := reflect.TypeOf(jsonModel)
+	for i := 0; i < jsonType.NumField(); i++ {
+		jsonField := jsonType.Field(i)
+		tfField, ok := tfType.FieldByName(jsonField.Name)
+		if name := jsonFieldName(jsonField); ok && name != "" {
+			attributes[name] = tfField.Tag.Get("tfsdk")
+		}
+	}
+	return attributes
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// stringMapValues returns the known values of a map of strings.
+func stringMapValues(m types.Map) map[string]string {
+	if m.IsNull() || m.IsUnknown() {
+		return nil
+	}
+
+	values := map[string]string{}
+	for key, element := range m.Elements() {
+		if s, ok := element.(types.String); ok && !s.IsNull() && !s.IsUnknown() {
+			values[key] = s.ValueString()
+		}
+	}
+	return values
+}
+
+// stringMapValue converts values into a map of strings, an empty map becomes null.
+func stringMapValue(values map[string]string) types.Map {
+	if len(values) == 0 {
+		return types.MapNull(types.StringType)
+	}
+
+	elements := make(map[string]attr.Value, len(values))
+	for key, value := range values {
+		elements[key] = types.StringValue(value)
+	}
+	return types.MapValueMust(types.StringType, elements)
+}
+
+// configuredInputs returns the returned inputs whose ids are in prior. Azure DevOps fills in defaults
+// for inputs which are not configured, such inputs would otherwise show up as drift.
+func configuredInputs(returned, prior types.Map) types.Map {
+	priorInputs := stringMapValues(prior)
+	inputs := map[string]string{}
+	for id, value := range stringMapValues(returned) {
+		if _, ok := priorInputs[id]; ok {
+			inputs[id] = value
+		}
+	}
+	return stringMapValue(inputs)
+}
+
+// mergeInputMaps merges the given input maps into one, later maps win. Duplicate ids are
+// rejected during config validation.
+func mergeInputMaps(maps ...map[string]string) map[string]string {
+	merged := map[string]string{}
+	for _, m := range maps {
+		for id, value := range m {
+			merged[id] = value
+		}
+	}
+
+	if len(merged) == 0 {
+		return nil
+	}
+	return merged
+}
+
+// formatHeaders serializes HTTP headers the way Azure DevOps expects them: one "Name:Value" pair
+// per line. Headers are sorted by name so the result is canonical.
+func formatHeaders(headers map[string]string) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		lines = append(lines, name+":"+headers[name])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseHeaders parses newline-separated "Name:Value" pairs. Surrounding whitespace of names and
+// values is dropped, lines without a name are skipped.
+func parseHeaders(s string) map[string]string {
+	headers := map[string]string{}
+	for _, line := range strings.Split(s, "\n") {
+		name, value, _ := strings.Cut(line, ":")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		headers[name] = strings.TrimSpace(value)
+	}
+	return headers
+}
+
+// findHeader returns the name under which a header is set, header names are case-insensitive.
+func findHeader(headers map[string]string, name string) (string, bool) {
+	if _, ok := headers[name]; ok {
+		return name, true
+	}
+	for candidate := range headers {
+		if strings.EqualFold(candidate, name) {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// isMasked reports whether Azure DevOps replaced a secret value with asterisks in its response.
+func isMasked(value string) bool {
+	return value != "" && strings.Trim(value, "*") == ""
+}
+
+// isSecretHeader reports whether a header name suggests that its value is a credential, such as
+// Authorization or X-Api-Key.
+func isSecretHeader(name string) bool {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "authorization" || name == "proxy-authorization" {
+		return true
+	}
+	for _, suffix := range []string{"-key", "-token", "-secret"} {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
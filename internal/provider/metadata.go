@@ -0,0 +1,270 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// EventTypeDescriptor describes an event type of a service hook publisher.
+type EventTypeDescriptor struct {
+	ID                        string            `json:"id"`
+	Name                      string            `json:"name"`
+	PublisherId               string            `json:"publisherId"`
+	InputDescriptors          []InputDescriptor `json:"inputDescriptors"`
+	SupportedResourceVersions []string          `json:"supportedResourceVersions"`
+}
+
+// ConsumerActionDescriptor describes an action of a service hook consumer.
+type ConsumerActionDescriptor struct {
+	ID                        string              `json:"id"`
+	Name                      string              `json:"name"`
+	ConsumerId                string              `json:"consumerId"`
+	InputDescriptors          []InputDescriptor   `json:"inputDescriptors"`
+	SupportedEventTypes       []string            `json:"supportedEventTypes"`
+	SupportedResourceVersions map[string][]string `json:"supportedResourceVersions"`
+}
+
+// InputDescriptor describes an input of an event type or consumer action.
+type InputDescriptor struct {
+	ID             string           `json:"id"`
+	Name           string           `json:"name"`
+	IsConfidential bool             `json:"isConfidential"`
+	Validation     *InputValidation `json:"validation,omitempty"`
+	Values         *InputValues     `json:"values,omitempty"`
+}
+
+// InputValidation holds the constraints of an input.
+type InputValidation struct {
+	IsRequired                  bool   `json:"isRequired"`
+	MaxLength                   *int   `json:"maxLength,omitempty"`
+	MinLength                   *int   `json:"minLength,omitempty"`
+	Pattern                     string `json:"pattern,omitempty"`
+	PatternMismatchErrorMessage string `json:"patternMismatchErrorMessage,omitempty"`
+}
+
+// InputValues holds the default and possible values of an input.
+type InputValues struct {
+	DefaultValue              string       `json:"defaultValue,omitempty"`
+	IsLimitedToPossibleValues bool         `json:"isLimitedToPossibleValues"`
+	PossibleValues            []InputValue `json:"possibleValues,omitempty"`
+}
+
+// InputValue is a possible value of an input.
+type InputValue struct {
+	Value        string `json:"value"`
+	DisplayValue string `json:"displayValue,omitempty"`
+}
+
+type metadataCache struct {
+	mu        sync.Mutex
+	events    map[string][]EventTypeDescriptor
+	consumers map[string][]ConsumerActionDescriptor
+}
+
+func newMetadataCache() *metadataCache {
+	return &metadataCache{
+		events:    map[string][]EventTypeDescriptor{},
+		consumers: map[string][]ConsumerActionDescriptor{},
+	}
+}
+
+func (m *metadataCache) eventTypes(key string) ([]EventTypeDescriptor, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	eventTypes, ok := m.events[key]
+	return eventTypes, ok
+}
+
+func (m *metadataCache) setEventTypes(key string, eventTypes []EventTypeDescriptor) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events[key] = eventTypes
+}
+
+func (m *metadataCache) consumerActions(key string) ([]ConsumerActionDescriptor, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	actions, ok := m.consumers[key]
+	return actions, ok
+}
+
+func (m *metadataCache) setConsumerActions(key string, actions []ConsumerActionDescriptor) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.consumers[key] = actions
+}
+
+func findEventType(eventTypes []EventTypeDescriptor, id string) *EventTypeDescriptor {
+	for i := range eventTypes {
+		if eventTypes[i].ID == id {
+			return &eventTypes[i]
+		}
+	}
+	return nil
+}
+
+// latestResourceVersion returns the newest released resource version, or the newest preview if
+// there is no release. Previews change their payload without notice, so they are only used when
+// configured or when the event type has nothing else. Returns an empty string if there are none.
+func latestResourceVersion(versions []string) string {
+	latest, latestPreview := "", ""
+	for _, version := range versions {
+		if strings.Contains(version, "-") {
+			if latestPreview == "" || compareResourceVersions(version, latestPreview) > 0 {
+				latestPreview = version
+			}
+		} else if latest == "" || compareResourceVersions(version, latest) > 0 {
+			latest = version
+		}
+	}
+
+	if latest == "" {
+		return latestPreview
+	}
+	return latest
+}
+
+// compareResourceVersions compares resource versions of the form major.minor[-preview[.n]],
+// returning a negative number, zero or a positive number like strings.Compare.
+func compareResourceVersions(a, b string) int {
+	aRelease, aPreview, aIsPreview := strings.Cut(a, "-")
+	bRelease, bPreview, bIsPreview := strings.Cut(b, "-")
+
+	if c := compareNumbers(strings.Split(aRelease, "."), strings.Split(bRelease, ".")); c != 0 {
+		return c
+	}
+	switch {
+	case aIsPreview && !bIsPreview:
+		return -1
+	case !aIsPreview && bIsPreview:
+		return 1
+	}
+	return compareNumbers(strings.Split(aPreview, "."), strings.Split(bPreview, "."))
+}
+
+// compareNumbers compares dot-separated version parts numerically, missing parts count as zero and
+// parts which are not numbers are compared as strings.
+func compareNumbers(a, b []string) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y string
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+
+		m, errX := strconv.Atoi(x)
+		n, errY := strconv.Atoi(y)
+		switch {
+		case (errX == nil || x == "") && (errY == nil || y == ""):
+			if m != n {
+				return m - n
+			}
+		case x != y:
+			return strings.Compare(x, y)
+		}
+	}
+	return 0
+}
+
+func findConsumerAction(actions []ConsumerActionDescriptor, id string) *ConsumerActionDescriptor {
+	for i := range actions {
+		if actions[i].ID == id {
+			return &actions[i]
+		}
+	}
+	return nil
+}
+
+// supportsEventType reports whether the action can be triggered by the event type.
+func (a *ConsumerActionDescriptor) supportsEventType(eventType string) bool {
+	if len(a.SupportedEventTypes) == 0 {
+		return true
+	}
+	for _, supported := range a.SupportedEventTypes {
+		if supported == "*" || supported == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// validateInputs checks the planned inputs, keyed by input id, against the input descriptors.
+// Unknown values are only checked for presence.
+func validateInputs(descriptors []InputDescriptor, inputs map[string]types.String, attribute path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for _, descriptor := range descriptors {
+		value, ok := inputs[descriptor.ID]
+		present := ok && !value.IsNull()
+
+		if !present {
+			// Inputs with a default value are filled in by Azure DevOps
+			if descriptor.Validation != nil && descriptor.Validation.IsRequired && (descriptor.Values == nil || descriptor.Values.DefaultValue == "") {
+				diags.AddAttributeError(attribute, "Missing required input",
+					fmt.Sprintf("The input %q (%s) is required.", descriptor.ID, descriptor.Name))
+			}
+			continue
+		}
+
+		if value.IsUnknown() {
+			continue
+		}
+
+		if err := validateInputValue(descriptor, value.ValueString()); err != "" {
+			diags.AddAttributeError(attribute, "Invalid input value",
+				fmt.Sprintf("The input %q (%s) %s.", descriptor.ID, descriptor.Name, err))
+		}
+	}
+
+	return diags
+}
+
+// validateInputValue returns why the value violates the descriptor, or an empty string.
+func validateInputValue(descriptor InputDescriptor, value string) string {
+	if validation := descriptor.Validation; validation != nil {
+		if validation.MinLength != nil && len(value) < *validation.MinLength {
+			return fmt.Sprintf("must be at least %d characters long", *validation.MinLength)
+		}
+
+		if validation.MaxLength != nil && len(value) > *validation.MaxLength {
+			return fmt.Sprintf("must be at most %d characters long", *validation.MaxLength)
+		}
+
+		// Patterns are written for .NET, skip the ones Go cannot compile rather than guessing
+		if validation.Pattern != "" {
+			if pattern, err := regexp.Compile(validation.Pattern); err == nil && !pattern.MatchString(value) {
+				if validation.PatternMismatchErrorMessage != "" {
+					return "is invalid: " + validation.PatternMismatchErrorMessage
+				}
+				return fmt.Sprintf("must match %q", validation.Pattern)
+			}
+		}
+	}
+
+	if values := descriptor.Values; values != nil && values.IsLimitedToPossibleValues && len(values.PossibleValues) > 0 {
+		allowed := make([]string, 0, len(values.PossibleValues))
+		for _, possible := range values.PossibleValues {
+			if possible.Value == value {
+				return ""
+			}
+			allowed = append(allowed, possible.Value)
+		}
+		sort.Strings(allowed)
+		return "must be one of: " + strings.Join(allowed, ", ")
+	}
+
+	return ""
+}
@@ -0,0 +1,108 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestValidateInputs(t *testing.T) {
+	maxLength := 10
+	descriptors := []InputDescriptor{
+		{ID: "url", Name: "URL", Validation: &InputValidation{IsRequired: true, Pattern: "^https?://"}},
+		{ID: "messagesToSend", Name: "Messages to send", Values: &InputValues{
+			IsLimitedToPossibleValues: true,
+			PossibleValues:            []InputValue{{Value: "all"}, {Value: "none"}},
+		}},
+		{ID: "resourceDetailsToSend", Name: "Resource details", Validation: &InputValidation{IsRequired: true}, Values: &InputValues{DefaultValue: "all"}},
+		{ID: "basicAuthUsername", Name: "Username", Validation: &InputValidation{MaxLength: &maxLength}},
+	}
+
+	cases := map[string]struct {
+		inputs map[string]types.String
+		errors int
+	}{
+		"valid": {
+			inputs: map[string]types.String{"url": types.StringValue("https://example.com"), "messagesToSend": types.StringValue("all")},
+		},
+		"unknown": {
+			inputs: map[string]types.String{"url": types.StringUnknown()},
+		},
+		"missing required": {
+			inputs: map[string]types.String{"url": types.StringNull()},
+			errors: 1,
+		},
+		"invalid values": {
+			inputs: map[string]types.String{
+				"url":               types.StringValue("ftp://example.com"),
+				"messagesToSend":    types.StringValue("text"),
+				"basicAuthUsername": types.StringValue("much-too-long-user"),
+			},
+			errors: 3,
+		},
+	}
+
+	for name, c := range cases {
+		diags := validateInputs(descriptors, c.inputs, path.Root("consumer_inputs"))
+		if diags.ErrorsCount() != c.errors {
+			t.Errorf("%s: expected %d errors, got %v", name, c.errors, diags)
+		}
+	}
+}
+
+func TestConfidentialInputWarnings(t *testing.T) {
+	descriptors := []InputDescriptor{
+		{ID: "connectionString", Name: "SAS connection string", IsConfidential: true},
+		{ID: "queueName", Name: "Queue name"},
+	}
+
+	diags := confidentialInputWarnings(descriptors, stringMapValue(map[string]string{"connectionString": "Endpoint=sb://", "queueName": "events"}))
+	if diags.WarningsCount() != 1 || diags.HasError() {
+		t.Errorf("expected one warning, got %v", diags)
+	}
+
+	if diags := confidentialInputWarnings(descriptors, types.MapNull(types.StringType)); len(diags) != 0 {
+		t.Errorf("expected no warnings without inputs, got %v", diags)
+	}
+}
+
+func TestGetEventTypesCached(t *testing.T) {
+	requests := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`{"count":1,"value":[{"id":"git.push","publisherId":"tfs","supportedResourceVersions":["1.0"]}]}`))
+	})
+
+	for i := 0; i < 2; i++ {
+		eventTypes, err := client.WithOrganization("other").GetEventTypes("tfs")
+		if err != nil || findEventType(eventTypes, "git.push") == nil {
+			t.Fatalf("unexpected result: %v, %v", eventTypes, err)
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("expected metadata to be fetched once, got %d requests", requests)
+	}
+}
+
+func TestLatestResourceVersion(t *testing.T) {
+	cases := map[string][]string{
+		"":               nil,
+		"1.0":            {"1.0"},
+		"2.0":            {"1.0", "5.1-preview.1", "2.0"},
+		"5.1":            {"5.1-preview.2", "5.1", "5.1-preview.10"},
+		"1.10":           {"1.9", "1.10"},
+		"2.0-preview.10": {"2.0-preview.2", "2.0-preview.10", "2.0-preview"},
+	}
+
+	for expected, versions := range cases {
+		if latest := latestResourceVersion(versions); latest != expected {
+			t.Errorf("%v: expected %q, got %q", versions, expected, latest)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Failure modes of verify_on_apply.
+const (
+	verifyFailureModeError   = "error"
+	verifyFailureModeWarning = "warning"
+)
+
+var verifyFailureModes = []string{verifyFailureModeError, verifyFailureModeWarning}
+
+// Results of a notification. Notifications are pending until the consumer has been called.
+const (
+	notificationResultPending   = "pending"
+	notificationResultSucceeded = "succeeded"
+)
+
+// Polling of test notification results, variables to be shortened by tests.
+var (
+	notificationPollInterval = 2 * time.Second
+	notificationTimeout      = time.Minute
+)
+
+// maxResponseExcerpt limits the response body of a failed delivery shown in diagnostics.
+const maxResponseExcerpt = 500
+
+type Notification struct {
+	ID             int64                `json:"id,omitempty"`
+	SubscriptionId string               `json:"subscriptionId,omitempty"`
+	Status         string               `json:"status,omitempty"`
+	Result         string               `json:"result,omitempty"`
+	Details        *NotificationDetails `json:"details,omitempty"`
+}
+
+type NotificationDetails struct {
+	PublisherId      string           `json:"publisherId,omitempty"`
+	EventType        string           `json:"eventType,omitempty"`
+	ConsumerId       string           `json:"consumerId,omitempty"`
+	ConsumerActionId string           `json:"consumerActionId,omitempty"`
+	PublisherInputs  *PublisherInputs `json:"publisherInputs,omitempty"`
+	ConsumerInputs   *ConsumerInputs  `json:"consumerInputs,omitempty"`
+
+	// Set by Azure DevOps once the consumer has been called
+	Response     string `json:"response,omitempty"`
+	ErrorMessage string `json:"errorMessage,omitempty"`
+	ErrorDetail  string `json:"errorDetail,omitempty"`
+}
+
+// newTestNotification returns a test notification for the subscription as it was sent, so the
+// consumer is called with the secrets which Azure DevOps masks in its responses.
+func newTestNotification(subscriptionId string, subscription *WebhookSubscription) *Notification {
+	details := &NotificationDetails{
+		ConsumerId:      subscription.ConsumerId,
+		PublisherInputs: subscription.PublisherInputs,
+		ConsumerInputs:  subscription.ConsumerInputs,
+	}
+	if subscription.PublisherId != nil {
+		details.PublisherId = *subscription.PublisherId
+	}
+	if subscription.EventType != nil {
+		details.EventType = *subscription.EventType
+	}
+	if subscription.ConsumerActionId != nil {
+		details.ConsumerActionId = *subscription.ConsumerActionId
+	}
+
+	return &Notification{SubscriptionId: subscriptionId, Details: details}
+}
+
+// verifyDelivery sends a test notification for the subscription and waits for its result.
+func verifyDelivery(ctx context.Context, client *Client, subscriptionId string, subscription *WebhookSubscription) (*Notification, error) {
+	notification, err := client.SendTestNotification(newTestNotification(subscriptionId, subscription))
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(notificationTimeout)
+	for notification.Result == "" || notification.Result == notificationResultPending {
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("no result for test notification %d after %s", notification.ID, notificationTimeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(notificationPollInterval):
+		}
+
+		notification, err = client.GetNotification(subscriptionId, notification.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return notification, nil
+}
+
+var responseStatusPattern = regexp.MustCompile(`(?i)(?:HTTP/[\d.]+|status(?: code)?:?)\s+(\d{3})`)
+
+// deliveryFailure describes why a notification was not delivered, or returns an empty string if it was.
+func deliveryFailure(notification *Notification) string {
+	if notification.Result == notificationResultSucceeded {
+		return ""
+	}
+
+	detail := fmt.Sprintf("The test notification %d was not delivered (result %q)", notification.ID, notification.Result)
+	if notification.Details == nil {
+		return detail + "."
+	}
+
+	if m := responseStatusPattern.FindStringSubmatch(notification.Details.Response); m != nil {
+		detail += fmt.Sprintf(", the receiver responded with status code %s", m[1])
+	}
+	detail += "."
+
+	if notification.Details.ErrorMessage != "" {
+		detail += "\n\n" + notification.Details.ErrorMessage
+	}
+	if excerpt := responseExcerpt(notification.Details.Response); excerpt != "" {
+		detail += "\n\nResponse:\n" + excerpt
+	}
+	return detail
+}
+
+// responseExcerpt returns the beginning of the body of a response recorded by Azure DevOps.
+func responseExcerpt(response string) string {
+	body := response
+	for _, separator := range []string{"\r\n\r\n", "\n\n", "Content:"} {
+		if i := strings.Index(response, separator); i >= 0 {
+			body = response[i+len(separator):]
+			break
+		}
+	}
+
+	body = strings.TrimSpace(body)
+	if len(body) > maxResponseExcerpt {
+		// Cut in front of a character split by the byte limit
+		cut := maxResponseExcerpt
+		for cut > 0 && !utf8.RuneStart(body[cut]) {
+			cut--
+		}
+		body = body[:cut] + "..."
+	}
+	return body
+}
@@ -0,0 +1,89 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestVerifyDelivery(t *testing.T) {
+	interval := notificationPollInterval
+	notificationPollInterval = time.Millisecond
+	t.Cleanup(func() { notificationPollInterval = interval })
+
+	polls := 0
+	var sent Notification
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/org/_apis/hooks/testNotifications":
+			if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+				t.Error(err)
+			}
+			fmt.Fprint(w, `{"id": 7, "status": "queued", "result": "pending"}`)
+		case r.URL.Path == "/org/_apis/hooks/subscriptions/subscription/notifications/7":
+			polls++
+			if polls < 2 {
+				fmt.Fprint(w, `{"id": 7, "status": "processing", "result": "pending"}`)
+				return
+			}
+			fmt.Fprint(w, `{"id": 7, "status": "completed", "result": "failed", "details": {
+				"errorMessage": "The remote server returned an error: (503) Service Unavailable.",
+				"response": "HTTP/1.1 503 Service Unavailable\r\nContent-Type: text/plain\r\n\r\nreceiver is down"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	subscription := DefaultWebhookSubscription()
+	subscription.EventType = stringToPointer("git.push")
+	subscription.ConsumerActionId = stringToPointer("httpRequest")
+	subscription.ConsumerInputs = &ConsumerInputs{URL: stringToPointer("https://receiver.example.com/hook"), BasicAuthPassword: stringToPointer("secret")}
+
+	notification, err := verifyDelivery(context.Background(), client, "subscription", subscription)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sent.SubscriptionId != "subscription" || sent.Details.EventType != "git.push" || *sent.Details.ConsumerInputs.BasicAuthPassword != "secret" {
+		t.Errorf("unexpected test notification: %+v", sent)
+	}
+
+	detail := deliveryFailure(notification)
+	for _, want := range []string{"status code 503", "Service Unavailable.", "Response:\nreceiver is down"} {
+		if !strings.Contains(detail, want) {
+			t.Errorf("failure %q does not contain %q", detail, want)
+		}
+	}
+
+	if detail := deliveryFailure(&Notification{Result: notificationResultSucceeded}); detail != "" {
+		t.Errorf("expected no failure, got %q", detail)
+	}
+}
+
+func TestResponseExcerpt(t *testing.T) {
+	long := strings.Repeat("x", maxResponseExcerpt+10)
+	cases := map[string]string{
+		"":                                      "",
+		"HTTP/1.1 500\r\nA: b\r\n\r\n  oops \n": "oops",
+		"Status Code: 404\nContent: not here":   "not here",
+		long:                                    long[:maxResponseExcerpt] + "...",
+	}
+
+	// Multi-byte characters are not split at the limit
+	umlauts := "x" + strings.Repeat("ä", maxResponseExcerpt)
+	cases[umlauts] = umlauts[:maxResponseExcerpt-1] + "..."
+
+	for response, expected := range cases {
+		if excerpt := responseExcerpt(response); excerpt != expected || !utf8.ValidString(excerpt) {
+			t.Errorf("%q: expected %q, got %q", response, expected, excerpt)
+		}
+	}
+}
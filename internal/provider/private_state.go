@@ -0,0 +1,111 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// passwordPrivateStateKey is the private state key remembering the basic auth password last sent.
+const passwordPrivateStateKey = "basic_auth_password"
+
+// privateStateGetter is implemented by the private state of framework requests.
+type privateStateGetter interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+}
+
+// privateStateSetter is implemented by the private state of framework responses.
+type privateStateSetter interface {
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// passwordPrivateState records a salted hash of the basic auth password last sent to Azure DevOps
+// together with the modification date of the subscription it produced. Azure DevOps never returns
+// the password, so a newer modification date is the only sign that it may have been changed.
+type passwordPrivateState struct {
+	Salt         string `json:"salt"`
+	Hash         string `json:"hash"`
+	ModifiedDate string `json:"modified_date"`
+}
+
+// storePasswordPrivateState remembers the password sent with a create or update, or forgets it if
+// no password is set.
+func storePasswordPrivateState(ctx context.Context, private privateStateSetter, password *string, modifiedDate *string) diag.Diagnostics {
+	if password == nil {
+		return private.SetKey(ctx, passwordPrivateStateKey, nil)
+	}
+
+	var diags diag.Diagnostics
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		diags.AddError("Private State Error", fmt.Sprintf("Failed to generate password salt: %s", err))
+		return diags
+	}
+
+	stored := passwordPrivateState{
+		Salt: hex.EncodeToString(salt),
+		Hash: hashPassword(salt, *password),
+	}
+	if modifiedDate != nil {
+		stored.ModifiedDate = *modifiedDate
+	}
+
+	value, err := json.Marshal(stored)
+	if err != nil {
+		diags.AddError("Private State Error", fmt.Sprintf("Failed to encode password state: %s", err))
+		return diags
+	}
+
+	return private.SetKey(ctx, passwordPrivateStateKey, value)
+}
+
+// passwordDrifted reports whether the password in state can no longer be trusted, because it is not
+// the one last sent or the subscription was modified since. Without private state, e.g. for state
+// written by older provider versions, the password in state is trusted.
+func passwordDrifted(ctx context.Context, private privateStateGetter, password string, modifiedDate *string) (bool, diag.Diagnostics) {
+	value, diags := private.GetKey(ctx, passwordPrivateStateKey)
+	if diags.HasError() || value == nil {
+		return false, diags
+	}
+
+	var stored passwordPrivateState
+	if err := json.Unmarshal(value, &stored); err != nil {
+		diags.AddError("Private State Error", fmt.Sprintf("Failed to decode password state: %s", err))
+		return false, diags
+	}
+
+	salt, err := hex.DecodeString(stored.Salt)
+	if err != nil || subtle.ConstantTimeCompare([]byte(hashPassword(salt, password)), []byte(stored.Hash)) != 1 {
+		return true, diags
+	}
+
+	return modifiedDate != nil && !sameTimestamp(stored.ModifiedDate, *modifiedDate), diags
+}
+
+func hashPassword(salt []byte, password string) string {
+	hash := sha256.New()
+	hash.Write(salt)
+	hash.Write([]byte(password))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// sameTimestamp compares two timestamps of the API, falling back to a string comparison if they
+// cannot be parsed.
+func sameTimestamp(a, b string) bool {
+	timeA, errA := time.Parse(time.RFC3339Nano, a)
+	timeB, errB := time.Parse(time.RFC3339Nano, b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return timeA.Equal(timeB)
+}
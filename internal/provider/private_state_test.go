@@ -0,0 +1,60 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+type testPrivateState map[string][]byte
+
+func (p testPrivateState) GetKey(_ context.Context, key string) ([]byte, diag.Diagnostics) {
+	return p[key], nil
+}
+
+func (p testPrivateState) SetKey(_ context.Context, key string, value []byte) diag.Diagnostics {
+	if len(value) == 0 {
+		delete(p, key)
+	} else {
+		p[key] = value
+	}
+	return nil
+}
+
+func TestPasswordDrifted(t *testing.T) {
+	ctx := context.Background()
+	private := testPrivateState{}
+
+	// Without private state the password in state is trusted
+	if drifted, _ := passwordDrifted(ctx, private, "secret", stringToPointer("2024-11-01T10:00:00Z")); drifted {
+		t.Error("expected no drift without private state")
+	}
+
+	storePasswordPrivateState(ctx, private, stringToPointer("secret"), stringToPointer("2024-11-01T10:00:00.000Z"))
+
+	cases := []struct {
+		password     string
+		modifiedDate string
+		drifted      bool
+	}{
+		{"secret", "2024-11-01T10:00:00Z", false},
+		{"secret", "2024-11-02T08:30:00Z", true},
+		{"other", "2024-11-01T10:00:00Z", true},
+	}
+
+	for _, c := range cases {
+		drifted, diags := passwordDrifted(ctx, private, c.password, &c.modifiedDate)
+		if diags.HasError() || drifted != c.drifted {
+			t.Errorf("passwordDrifted(%q, %q) = %v, %v, expected %v", c.password, c.modifiedDate, drifted, diags, c.drifted)
+		}
+	}
+
+	storePasswordPrivateState(ctx, private, nil, nil)
+	if _, ok := private[passwordPrivateStateKey]; ok {
+		t.Error("expected password state to be removed")
+	}
+}
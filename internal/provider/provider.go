@@ -5,14 +5,21 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
@@ -21,8 +28,10 @@ var (
 )
 
 type azureDevopsWebhooksProviderModel struct {
-	Organization types.String `tfsdk:"organization"`
-	Pat          types.String `tfsdk:"pat"`
+	Organization         types.String `tfsdk:"organization"`
+	Pat                  types.String `tfsdk:"pat"`
+	PatAuthorizationId   types.String `tfsdk:"pat_authorization_id"`
+	PatExpiryWarningDays types.Int64  `tfsdk:"pat_expiry_warning_days"`
 }
 
 // New is a helper function to simplify provider server and testing implementation.
@@ -59,6 +68,19 @@ func (p *azureDevopsWebhooksProvider) Schema(_ context.Context, _ provider.Schem
 				Optional:  true,
 				Sensitive: true,
 			},
+			"pat_authorization_id": schema.StringAttribute{
+				Optional: true,
+				Description: "The authorization ID of the configured personal access token as listed by the PAT lifecycle API. " +
+					"Identifies the token whose expiry pat_expiry_warning_days checks when the identity has more than one active token.",
+			},
+			"pat_expiry_warning_days": schema.Int64Attribute{
+				Optional: true,
+				Description: "Emit a warning when the configured personal access token expires within this many days. " +
+					"The expiry is looked up with the PAT lifecycle API and skipped if the token is not permitted to use it, " +
+					"or if the identity has several active tokens and pat_authorization_id is not set. " +
+					"The check is disabled unless this is set to a positive number.",
+				Validators: []validator.Int64{int64validator.AtLeast(0)},
+			},
 		},
 	}
 }
@@ -152,12 +174,68 @@ func (p *azureDevopsWebhooksProvider) Configure(ctx context.Context, req provide
 		return
 	}
 
+	// The lifecycle API often rejects PATs, so it is only called when the check was asked for
+	if patExpiryWarningDays := config.PatExpiryWarningDays.ValueInt64(); patExpiryWarningDays > 0 {
+		warnExpiringPat(ctx, client, config.PatAuthorizationId.ValueString(), time.Duration(patExpiryWarningDays)*24*time.Hour, &resp.Diagnostics)
+	}
+
 	// Make the HashiCups client available during DataSource and Resource
 	// type Configure methods.
 	resp.DataSourceData = client
 	resp.ResourceData = client
 }
 
+// warnExpiringPat adds a warning if the configured personal access token expires within the given
+// window. The check is silently skipped if the lifecycle API is not available to the token or the
+// token cannot be identified among the active tokens of the identity.
+func warnExpiringPat(ctx context.Context, client *Client, authorizationId string, window time.Duration, diags *diag.Diagnostics) {
+	tokens, err := client.GetPersonalAccessTokens()
+	if err != nil {
+		tflog.Debug(ctx, "Skipping personal access token expiry check: "+err.Error())
+		return
+	}
+
+	token := configuredPat(tokens, authorizationId)
+	if token == nil {
+		tflog.Debug(ctx, "Skipping personal access token expiry check: the configured token cannot be identified, set pat_authorization_id")
+		return
+	}
+
+	now := time.Now()
+	if expiresWithin(*token, now, window) {
+		diags.AddAttributeWarning(
+			path.Root("pat"),
+			"AzureDevOps PAT Expires Soon",
+			fmt.Sprintf("The personal access token %q expires on %s (in %d days). "+
+				"Service hook subscriptions managed with this token cannot be refreshed or changed once it has expired, rotate it in time.",
+				token.DisplayName, token.ValidTo.Format(time.RFC3339), int(token.ValidTo.Sub(now).Hours()/24)),
+		)
+	}
+}
+
+// configuredPat returns the token with the given authorization ID. Without an ID the configured token
+// is only known if it is the single active token of the identity, otherwise nil is returned.
+func configuredPat(tokens []PersonalAccessToken, authorizationId string) *PersonalAccessToken {
+	if authorizationId == "" {
+		if len(tokens) == 1 {
+			return &tokens[0]
+		}
+		return nil
+	}
+
+	for i := range tokens {
+		if strings.EqualFold(tokens[i].AuthorizationId, authorizationId) {
+			return &tokens[i]
+		}
+	}
+	return nil
+}
+
+// expiresWithin reports whether the token is still valid at now but expires within the given window.
+func expiresWithin(token PersonalAccessToken, now time.Time, window time.Duration) bool {
+	return token.ValidTo.After(now) && token.ValidTo.Before(now.Add(window))
+}
+
 // DataSources defines the data sources implemented in the provider.
 func (p *azureDevopsWebhooksProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{}
@@ -3,6 +3,17 @@
 
 package provider
 
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
 // testAccProtoV6ProviderFactories are used to instantiate a provider during
 // acceptance testing. The factory function will be invoked for every Terraform
 // CLI command executed to create a provider server to which the CLI can
@@ -16,3 +27,68 @@ package provider
 // 	// about the appropriate environment variables being set are common to see in a pre-check
 // 	// function.
 // }
+
+func TestConfiguredPat(t *testing.T) {
+	tokens := []PersonalAccessToken{{AuthorizationId: "a1", DisplayName: "ci"}, {AuthorizationId: "b2", DisplayName: "local"}}
+
+	if token := configuredPat(tokens, "B2"); token == nil || token.DisplayName != "local" {
+		t.Errorf("expected the token with the configured authorization ID, got %+v", token)
+	}
+	if token := configuredPat(tokens, ""); token != nil {
+		t.Errorf("expected no token without an authorization ID among several tokens, got %+v", token)
+	}
+	if token := configuredPat(tokens[:1], ""); token == nil || token.DisplayName != "ci" {
+		t.Errorf("expected the single active token, got %+v", token)
+	}
+	if token := configuredPat(tokens, "unknown"); token != nil {
+		t.Errorf("expected no token for an unknown authorization ID, got %+v", token)
+	}
+}
+
+func TestExpiresWithin(t *testing.T) {
+	now := time.Date(2024, 11, 1, 12, 0, 0, 0, time.UTC)
+	cases := map[time.Duration]bool{
+		-time.Hour:           false,
+		24 * time.Hour:       true,
+		7 * 24 * time.Hour:   true,
+		365 * 24 * time.Hour: false,
+	}
+
+	for validFor, expected := range cases {
+		if actual := expiresWithin(PersonalAccessToken{ValidTo: now.Add(validFor)}, now, 14*24*time.Hour); actual != expected {
+			t.Errorf("valid for %s: expected %t, got %t", validFor, expected, actual)
+		}
+	}
+}
+
+func TestWarnExpiringPat(t *testing.T) {
+	validTo := time.Now().Add(3 * 24 * time.Hour).UTC().Format(time.RFC3339)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/org/_apis/tokens/pats" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"patTokens": [
+			{"authorizationId": "a1", "displayName": "terraform", "validTo": %q},
+			{"authorizationId": "b2", "displayName": "unrelated", "validTo": %q}]}`, validTo, validTo)
+	})
+
+	var diags diag.Diagnostics
+	warnExpiringPat(context.Background(), client, "a1", 14*24*time.Hour, &diags)
+	if diags.WarningsCount() != 1 || !strings.Contains(diags[0].Detail(), `"terraform"`) {
+		t.Errorf("expected one warning about the configured token, got %v", diags)
+	}
+
+	// Several active tokens and no authorization ID, the configured token is unknown
+	diags = nil
+	warnExpiringPat(context.Background(), client, "", 14*24*time.Hour, &diags)
+	if len(diags) != 0 {
+		t.Errorf("expected no warning without an identified token, got %v", diags)
+	}
+
+	diags = nil
+	warnExpiringPat(context.Background(), client, "a1", 24*time.Hour, &diags)
+	if len(diags) != 0 {
+		t.Errorf("expected no warning outside the window, got %v", diags)
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Azure DevOps normalizes some inputs, so the value it returns may differ from the value sent
+// although both mean the same. Such inputs use string types with semantic equality, which makes the
+// framework keep the configured value instead of reporting a difference.
+type (
+	GUIDType   = normalizedStringType[guidNormalization]
+	GUIDValue  = normalizedStringValue[guidNormalization]
+	BranchType = normalizedStringType[branchNormalization]
+	// BranchValue is equal to the same branch with or without the refs/heads/ prefix.
+	BranchValue = normalizedStringValue[branchNormalization]
+	URLType     = normalizedStringType[urlNormalization]
+	// URLValue is equal to the same URL with or without a trailing slash.
+	URLValue = normalizedStringValue[urlNormalization]
+)
+
+// normalization returns the canonical form of a string, values with the same canonical form are
+// semantically equal.
+type normalization interface {
+	name() string
+	normalize(string) string
+}
+
+type guidNormalization struct{}
+
+func (guidNormalization) name() string { return "GUID" }
+
+func (guidNormalization) normalize(s string) string { return strings.ToLower(s) }
+
+type branchNormalization struct{}
+
+func (branchNormalization) name() string { return "Branch" }
+
+func (branchNormalization) normalize(s string) string { return strings.TrimPrefix(s, "refs/heads/") }
+
+type urlNormalization struct{}
+
+func (urlNormalization) name() string { return "URL" }
+
+func (urlNormalization) normalize(s string) string { return strings.TrimSuffix(s, "/") }
+
+var (
+	_ basetypes.StringTypable                    = GUIDType{}
+	_ basetypes.StringValuableWithSemanticEquals = GUIDValue{}
+)
+
+type normalizedStringType[N normalization] struct {
+	basetypes.StringType
+}
+
+func (t normalizedStringType[N]) Equal(o attr.Type) bool {
+	_, ok := o.(normalizedStringType[N])
+	return ok
+}
+
+func (t normalizedStringType[N]) String() string {
+	var n N
+	return n.name() + "Type"
+}
+
+func (t normalizedStringType[N]) ValueFromString(_ context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return normalizedStringValue[N]{StringValue: in}, nil
+}
+
+func (t normalizedStringType[N]) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+
+	return normalizedStringValue[N]{StringValue: stringValue}, nil
+}
+
+func (t normalizedStringType[N]) ValueType(_ context.Context) attr.Value {
+	return normalizedStringValue[N]{}
+}
+
+type normalizedStringValue[N normalization] struct {
+	basetypes.StringValue
+}
+
+func (v normalizedStringValue[N]) Equal(o attr.Value) bool {
+	other, ok := o.(normalizedStringValue[N])
+	return ok && v.StringValue.Equal(other.StringValue)
+}
+
+func (v normalizedStringValue[N]) Type(_ context.Context) attr.Type {
+	return normalizedStringType[N]{}
+}
+
+func (v normalizedStringValue[N]) StringSemanticEquals(_ context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(normalizedStringValue[N])
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T but got %T.", v, newValuable),
+		)
+		return false, diags
+	}
+
+	var n N
+	return n.normalize(v.ValueString()) == n.normalize(newValue.ValueString()), diags
+}
+
+// normalizedStringPointerValue returns a null value for nil, like types.StringPointerValue.
+func normalizedStringPointerValue[N normalization](s *string) normalizedStringValue[N] {
+	return normalizedStringValue[N]{StringValue: basetypes.NewStringPointerValue(s)}
+}
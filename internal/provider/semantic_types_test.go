@@ -0,0 +1,61 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestSemanticEquality(t *testing.T) {
+	cases := []struct {
+		prior, new basetypes.StringValuableWithSemanticEquals
+		equal      bool
+	}{
+		{GUIDValue{StringValue: types.StringValue("5E2B2A5F-1C3D")}, GUIDValue{StringValue: types.StringValue("5e2b2a5f-1c3d")}, true},
+		{GUIDValue{StringValue: types.StringValue("5e2b2a5f-1c3d")}, GUIDValue{StringValue: types.StringValue("5e2b2a5f-1c3e")}, false},
+		{BranchValue{StringValue: types.StringValue("main")}, BranchValue{StringValue: types.StringValue("refs/heads/main")}, true},
+		{BranchValue{StringValue: types.StringValue("refs/heads/main")}, BranchValue{StringValue: types.StringValue("main")}, true},
+		{BranchValue{StringValue: types.StringValue("main")}, BranchValue{StringValue: types.StringValue("refs/tags/main")}, false},
+		{URLValue{StringValue: types.StringValue("https://receiver.example.com/hook")}, URLValue{StringValue: types.StringValue("https://receiver.example.com/hook/")}, true},
+		{URLValue{StringValue: types.StringValue("https://receiver.example.com/hook")}, URLValue{StringValue: types.StringValue("https://receiver.example.com/Hook")}, false},
+	}
+
+	for _, c := range cases {
+		equal, diags := c.prior.StringSemanticEquals(context.Background(), c.new)
+		if diags.HasError() || equal != c.equal {
+			t.Errorf("%v, %v: expected %t, got %t (%v)", c.prior, c.new, c.equal, equal, diags)
+		}
+	}
+
+	// Values of different types are never equal
+	if _, diags := (GUIDValue{}).StringSemanticEquals(context.Background(), URLValue{}); !diags.HasError() {
+		t.Error("expected an error comparing a GUID with a URL")
+	}
+}
+
+func TestNormalizedStringType(t *testing.T) {
+	ctx := context.Background()
+	value, err := GUIDType{}.ValueFromTerraform(ctx, tftypes.NewValue(tftypes.String, "AAAA"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	guid, ok := value.(GUIDValue)
+	if !ok || guid.ValueString() != "AAAA" || !guid.Type(ctx).Equal(GUIDType{}) || guid.Type(ctx).Equal(URLType{}) {
+		t.Errorf("unexpected value %#v", value)
+	}
+
+	// Equal stays exact, so changes of case still reach the state
+	for _, other := range []attr.Value{GUIDValue{StringValue: types.StringValue("aaaa")}, types.StringValue("AAAA")} {
+		if guid.Equal(other) {
+			t.Errorf("expected %v to differ from %v", guid, other)
+		}
+	}
+}
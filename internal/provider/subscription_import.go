@@ -0,0 +1,280 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ResourceWithImportState = &SubscriptionResource{}
+
+// ImportState imports a subscription by its id or by a key of its project, repository, event type and
+// target URL, see parseImportID.
+func (r *SubscriptionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	key, err := parseImportID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+
+	client := r.clientFor(types.StringValue(key.Organization))
+
+	webhookID, err := resolveImportKey(client, key)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to resolve import ID", err.Error())
+		return
+	}
+
+	// Use the client to fetch the webhook details using the ID
+	webhookResponse, err := client.GetWebhook(webhookID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Client Error",
+			fmt.Sprintf("Failed to get webhook: %s", err),
+		)
+		return
+	}
+
+	// Map the response to the model
+	var data = *ConvertToTFModel(webhookResponse)
+	data.Organization = types.StringValue(client.Organization)
+	// Set the imported state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// importKey identifies the subscription to import, either by ID or by its project, repository,
+// event type and URL.
+type importKey struct {
+	Organization string
+	ID           string
+	Project      string
+	Repository   string
+	EventType    string
+	URL          string
+}
+
+// parseImportID parses import IDs of the forms
+//
+//	<subscription id>
+//	<project>/<repository>/<event type>/<url>
+//
+// each optionally prefixed by "<organization>:". The repository may be left empty for subscriptions
+// without a repository filter. Projects and repositories may be given by name or ID.
+func parseImportID(id string) (importKey, error) {
+	var key importKey
+
+	// Colons of the URL follow a slash, so only a colon in front of the first slash separates the organization
+	if colon := strings.Index(id, ":"); colon > 0 && !strings.Contains(id[:colon], "/") {
+		key.Organization, id = id[:colon], id[colon+1:]
+	}
+
+	if !strings.Contains(id, "/") {
+		if id == "" {
+			return key, fmt.Errorf("missing subscription ID in import ID")
+		}
+		key.ID = id
+		return key, nil
+	}
+
+	parts := strings.SplitN(id, "/", 4)
+	if len(parts) != 4 || parts[0] == "" || parts[2] == "" || parts[3] == "" {
+		return key, fmt.Errorf("expected an import ID of the form <subscription id> or <project>/<repository>/<event type>/<url>, optionally prefixed by <organization>:, got %q", id)
+	}
+
+	key.Project, key.Repository, key.EventType, key.URL = parts[0], parts[1], parts[2], parts[3]
+	return key, nil
+}
+
+// resolveImportKey returns the ID of the subscription identified by key.
+func resolveImportKey(client *Client, key importKey) (string, error) {
+	if key.ID != "" {
+		return key.ID, nil
+	}
+
+	project, err := client.GetProjectGuid(key.Project)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve project %q: %w", key.Project, err)
+	}
+
+	query := subscriptionQuery{EventType: key.EventType, ProjectId: project.ID, URL: key.URL}
+	if key.Repository != "" {
+		repository, err := client.GetRepositoryGuid(key.Project, key.Repository)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve repository %q: %w", key.Repository, err)
+		}
+		query.RepositoryId = repository.ID
+	}
+
+	subscriptions, err := client.ListWebhooks(key.EventType)
+	if err != nil {
+		return "", err
+	}
+
+	return query.single(subscriptions)
+}
+
+// subscriptionQuery describes the subscriptions of an event type in a project, optionally restricted
+// to a repository, which deliver to a URL.
+type subscriptionQuery struct {
+	EventType    string
+	ProjectId    string
+	RepositoryId string
+	URL          string
+}
+
+// matches reports whether ws is described by the query. An empty repository only matches
+// subscriptions without a repository filter. Values are compared like their attributes, see
+// semantic_types.go.
+func (q subscriptionQuery) matches(ws *WebhookSubscription) bool {
+	if ws.ID == nil || ws.EventType == nil || *ws.EventType != q.EventType {
+		return false
+	}
+
+	var projectId, repositoryId, url string
+	if ws.PublisherInputs != nil {
+		projectId = derefString(ws.PublisherInputs.ProjectId)
+		repositoryId = derefString(ws.PublisherInputs.RepositoryId)
+	}
+	if ws.ConsumerInputs != nil {
+		url = derefString(ws.ConsumerInputs.URL)
+	}
+
+	return strings.EqualFold(projectId, q.ProjectId) && strings.EqualFold(repositoryId, q.RepositoryId) &&
+		urlNormalization{}.normalize(url) == urlNormalization{}.normalize(q.URL)
+}
+
+// filter returns the subscriptions matching the query.
+func (q subscriptionQuery) filter(subscriptions []WebhookSubscription) []*WebhookSubscription {
+	var matches []*WebhookSubscription
+	for i := range subscriptions {
+		if q.matches(&subscriptions[i]) {
+			matches = append(matches, &subscriptions[i])
+		}
+	}
+	return matches
+}
+
+// single returns the ID of the only subscription matching the query, failing with the list of
+// candidates if there is more than one.
+func (q subscriptionQuery) single(subscriptions []WebhookSubscription) (string, error) {
+	matches := q.filter(subscriptions)
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no %s subscription found in project %s delivering to %s", q.EventType, q.ProjectId, q.URL)
+	case 1:
+		return *matches[0].ID, nil
+	}
+
+	return "", ambiguousSubscriptionsError(matches, "import one of them by ID")
+}
+
+// ambiguousSubscriptionsError lists the candidates of an ambiguous match.
+func ambiguousSubscriptionsError(matches []*WebhookSubscription, hint string) error {
+	candidates := make([]string, len(matches))
+	for i, ws := range matches {
+		candidates[i] = fmt.Sprintf("%s (consumer %s, action %s, branch %q, created %s)",
+			*ws.ID, ws.ConsumerId, derefString(ws.ConsumerActionId), branchOf(ws), derefString(ws.CreatedDate))
+	}
+
+	return fmt.Errorf("%d subscriptions match, %s:\n  %s", len(matches), hint, strings.Join(candidates, "\n  "))
+}
+
+// findAdoptableSubscription returns the ID of the existing subscription identical to plan, see
+// identicalSubscription, or nil if there is none. More than one match is an error, as it is unclear
+// which one to adopt.
+func findAdoptableSubscription(client *Client, plan *WebhookSubscriptionTF) (*string, error) {
+	planned := ConvertToJSONModel(plan)
+	subscriptions, err := client.ListWebhooks(derefString(planned.EventType))
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*WebhookSubscription
+	for i := range subscriptions {
+		if identicalSubscription(planned, &subscriptions[i]) {
+			matches = append(matches, &subscriptions[i])
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		return matches[0].ID, nil
+	}
+
+	return nil, ambiguousSubscriptionsError(matches, "delete the duplicates or import one of them instead of adopting")
+}
+
+// identicalSubscription reports whether the existing subscription has the publisher, event type,
+// consumer and action of the planned one and the same identifying inputs: the typed publisher inputs,
+// the URL and the planned free-form inputs. A typed input which is not planned must not be set on the
+// existing subscription either, so adopting it never drops a filter or target.
+func identicalSubscription(planned, existing *WebhookSubscription) bool {
+	if existing.ID == nil || derefString(existing.EventType) != derefString(planned.EventType) ||
+		derefString(existing.PublisherId) != derefString(planned.PublisherId) || existing.ConsumerId != planned.ConsumerId ||
+		derefString(existing.ConsumerActionId) != derefString(planned.ConsumerActionId) {
+		return false
+	}
+
+	plannedPublisher, existingPublisher := derefInputs(planned.PublisherInputs), derefInputs(existing.PublisherInputs)
+	plannedConsumer, existingConsumer := derefInputs(planned.ConsumerInputs), derefInputs(existing.ConsumerInputs)
+
+	return publisherIdentity(plannedPublisher) == publisherIdentity(existingPublisher) &&
+		urlNormalization{}.normalize(derefString(plannedConsumer.URL)) == urlNormalization{}.normalize(derefString(existingConsumer.URL)) &&
+		plannedInputsMatch(plannedPublisher.Additional, existingPublisher.Additional) &&
+		plannedInputsMatch(plannedConsumer.Additional, existingConsumer.Additional)
+}
+
+// publisherIdentity returns the typed publisher inputs normalized like their attributes, unset and
+// empty inputs are the same. The tfsSubscriptionId is assigned by Azure DevOps and does not identify
+// a subscription.
+func publisherIdentity(pi PublisherInputs) [4]string {
+	return [4]string{
+		guidNormalization{}.normalize(derefString(pi.RepositoryId)),
+		branchNormalization{}.normalize(derefString(pi.Branch)),
+		derefString(pi.PushedBy),
+		guidNormalization{}.normalize(derefString(pi.ProjectId)),
+	}
+}
+
+// plannedInputsMatch reports whether the existing free-form inputs have the planned values. Inputs
+// which are not planned are ignored, as Azure DevOps fills in defaults for them, see configuredInputs.
+// Secrets masked by Azure DevOps cannot be compared, they only need to be set.
+func plannedInputsMatch(planned, existing map[string]string) bool {
+	for id, value := range planned {
+		if e := existing[id]; e != value && (value == "" || !isMasked(e)) {
+			return false
+		}
+	}
+	return true
+}
+
+// derefInputs returns the inputs, or empty inputs if they are not set.
+func derefInputs[T any](inputs *T) T {
+	var empty T
+	if inputs == nil {
+		return empty
+	}
+	return *inputs
+}
+
+func branchOf(ws *WebhookSubscription) string {
+	if ws.PublisherInputs == nil {
+		return ""
+	}
+	return derefString(ws.PublisherInputs.Branch)
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
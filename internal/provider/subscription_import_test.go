@@ -0,0 +1,262 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestParseImportID(t *testing.T) {
+	cases := map[string]importKey{
+		"5e2b2a5f-1c3d-4e5f-8a9b-0c1d2e3f4a5b":           {ID: "5e2b2a5f-1c3d-4e5f-8a9b-0c1d2e3f4a5b"},
+		"other-org:5e2b2a5f-1c3d-4e5f-8a9b-0c1d2e3f4a5b": {Organization: "other-org", ID: "5e2b2a5f-1c3d-4e5f-8a9b-0c1d2e3f4a5b"},
+		"My Project/app/git.push/https://receiver.example.com/hook": {
+			Project: "My Project", Repository: "app", EventType: "git.push", URL: "https://receiver.example.com/hook",
+		},
+		"other-org:My Project//build.complete/https://receiver.example.com/hook?a=b": {
+			Organization: "other-org", Project: "My Project", EventType: "build.complete", URL: "https://receiver.example.com/hook?a=b",
+		},
+	}
+
+	for id, expected := range cases {
+		key, err := parseImportID(id)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", id, err)
+		} else if key != expected {
+			t.Errorf("%q: expected %+v, got %+v", id, expected, key)
+		}
+	}
+
+	for _, id := range []string{"", "org:", "project/repository/git.push", "project/repository//https://receiver.example.com"} {
+		if _, err := parseImportID(id); err == nil {
+			t.Errorf("%q: expected an error", id)
+		}
+	}
+}
+
+func TestResolveImportKey(t *testing.T) {
+	subscription := func(id, repository, url string) string {
+		return fmt.Sprintf(`{"id": %q, "eventType": "git.push", "consumerId": "webHooks", "consumerActionId": "httpRequest",
+			"publisherInputs": {"projectId": "AAAA", "repository": %q}, "consumerInputs": {"url": %q}}`, id, repository, url)
+	}
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/_apis/projects/My Project"):
+			fmt.Fprint(w, `{"id": "aaaa"}`)
+		case strings.HasSuffix(r.URL.Path, "/_apis/git/repositories/app"):
+			fmt.Fprint(w, `{"id": "bbbb"}`)
+		case strings.HasSuffix(r.URL.Path, "/_apis/hooks/subscriptions") && r.URL.Query().Get("eventType") == "git.push":
+			fmt.Fprintf(w, `{"value": [%s, %s, %s, %s]}`,
+				subscription("first", "bbbb", "https://receiver.example.com/hook"),
+				subscription("other-url", "bbbb", "https://receiver.example.com/other"),
+				subscription("all-repositories", "", "https://receiver.example.com/hook"),
+				subscription("second", "BBBB", "https://receiver.example.com/other"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	id, err := resolveImportKey(client, importKey{Project: "My Project", Repository: "app", EventType: "git.push", URL: "https://receiver.example.com/hook"})
+	if err != nil || id != "first" {
+		t.Errorf("expected first, got %q (%v)", id, err)
+	}
+
+	id, err = resolveImportKey(client, importKey{Project: "My Project", EventType: "git.push", URL: "https://receiver.example.com/hook"})
+	if err != nil || id != "all-repositories" {
+		t.Errorf("expected all-repositories, got %q (%v)", id, err)
+	}
+
+	_, err = resolveImportKey(client, importKey{Project: "My Project", Repository: "app", EventType: "git.push", URL: "https://receiver.example.com/other"})
+	if err == nil || !strings.Contains(err.Error(), "other-url") || !strings.Contains(err.Error(), "second") {
+		t.Errorf("expected ambiguity error listing both candidates, got %v", err)
+	}
+
+	_, err = resolveImportKey(client, importKey{Project: "My Project", Repository: "app", EventType: "git.push", URL: "https://receiver.example.com/none"})
+	if err == nil {
+		t.Error("expected an error when no subscription matches")
+	}
+}
+
+func TestFindAdoptableSubscription(t *testing.T) {
+	subscription := func(id, branch, url string) string {
+		return fmt.Sprintf(`{"id": %q, "eventType": "git.push", "publisherId": "tfs", "consumerId": "webHooks", "consumerActionId": "httpRequest",
+			"publisherInputs": {"projectId": "aaaa", "repository": "bbbb", "branch": %q, "tfsSubscriptionId": "tfs"}, "consumerInputs": {"url": %q}}`, id, branch, url)
+	}
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"value": [%s, %s, %s]}`,
+			subscription("main", "main", "https://receiver.example.com/hook"),
+			subscription("release", "release", "https://receiver.example.com/hook"),
+			subscription("release-copy", "release", "https://receiver.example.com/hook"))
+	})
+
+	plan := func(branch, url string) *WebhookSubscriptionTF {
+		return &WebhookSubscriptionTF{
+			ConsumerActionId: types.StringValue("httpRequest"),
+			ConsumerId:       types.StringValue("webHooks"),
+			ConsumerInputs:   &ConsumerInputsTF{URL: URLValue{StringValue: types.StringValue(url)}},
+			EventType:        types.StringValue("git.push"),
+			PublisherId:      types.StringValue("tfs"),
+			PublisherInputs: &PublisherInputsTF{
+				Branch:       BranchValue{StringValue: types.StringValue(branch)},
+				ProjectId:    GUIDValue{StringValue: types.StringValue("AAAA")},
+				RepositoryId: GUIDValue{StringValue: types.StringValue("bbbb")},
+			},
+		}
+	}
+
+	id, err := findAdoptableSubscription(client, plan("main", "https://receiver.example.com/hook"))
+	if err != nil || id == nil || *id != "main" {
+		t.Errorf("expected to adopt main, got %v (%v)", id, err)
+	}
+
+	// Azure DevOps may return the branch as a ref and the URL with a trailing slash
+	id, err = findAdoptableSubscription(client, plan("refs/heads/main", "https://receiver.example.com/hook/"))
+	if err != nil || id == nil || *id != "main" {
+		t.Errorf("expected to adopt main for an equivalent branch and URL, got %v (%v)", id, err)
+	}
+
+	id, err = findAdoptableSubscription(client, plan("main", "https://receiver.example.com/other"))
+	if err != nil || id != nil {
+		t.Errorf("expected nothing to adopt, got %v (%v)", id, err)
+	}
+
+	// An unset branch must not adopt a subscription filtered to a branch
+	id, err = findAdoptableSubscription(client, plan("", "https://receiver.example.com/hook"))
+	if err != nil || id != nil {
+		t.Errorf("expected nothing to adopt without a branch, got %v (%v)", id, err)
+	}
+
+	_, err = findAdoptableSubscription(client, plan("release", "https://receiver.example.com/hook"))
+	if err == nil || !strings.Contains(err.Error(), "release-copy") {
+		t.Errorf("expected ambiguity error listing the candidates, got %v", err)
+	}
+}
+
+func TestFindAdoptableSubscriptionInputMaps(t *testing.T) {
+	subscription := func(id, queue string) string {
+		return fmt.Sprintf(`{"id": %q, "eventType": "git.push", "publisherId": "tfs", "consumerId": "azureServiceBus", "consumerActionId": "serviceBusQueueSend",
+			"publisherInputs": {"projectId": "aaaa", "branch": ""}, "consumerInputs": {"queueName": %q, "connectionString": "********"}}`, id, queue)
+	}
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"value": [%s, %s]}`, subscription("orders", "orders"), subscription("invoices", "invoices"))
+	})
+
+	plan := func(consumerInputs, sensitiveInputs map[string]string) *WebhookSubscriptionTF {
+		return &WebhookSubscriptionTF{
+			ConsumerActionId:           types.StringValue("serviceBusQueueSend"),
+			ConsumerId:                 types.StringValue("azureServiceBus"),
+			ConsumerInputsMap:          stringMapValue(consumerInputs),
+			EventType:                  types.StringValue("git.push"),
+			PublisherId:                types.StringValue("tfs"),
+			PublisherInputs:            &PublisherInputsTF{ProjectId: GUIDValue{StringValue: types.StringValue("aaaa")}},
+			SensitiveConsumerInputsMap: stringMapValue(sensitiveInputs),
+		}
+	}
+
+	// The masked connection string cannot be compared, only the queue tells the subscriptions apart
+	id, err := findAdoptableSubscription(client, plan(map[string]string{"queueName": "invoices"}, map[string]string{"connectionString": "Endpoint=sb://bus"}))
+	if err != nil || id == nil || *id != "invoices" {
+		t.Errorf("expected to adopt invoices, got %v (%v)", id, err)
+	}
+
+	id, err = findAdoptableSubscription(client, plan(map[string]string{"queueName": "payments"}, map[string]string{"connectionString": "Endpoint=sb://bus"}))
+	if err != nil || id != nil {
+		t.Errorf("expected nothing to adopt for another queue, got %v (%v)", id, err)
+	}
+}
+
+func TestFindAdoptableSubscriptionDefaults(t *testing.T) {
+	// Subscriptions created in the web UI have all inputs of the event type, unset ones are empty
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"value": [
+			{"id": "builds", "eventType": "build.complete", "publisherId": "tfs", "consumerId": "webHooks", "consumerActionId": "httpRequest",
+				"publisherInputs": {"buildStatus": "", "definitionName": "", "projectId": "aaaa", "tfsSubscriptionId": "cccc"},
+				"consumerInputs": {"url": "https://receiver.example.com/builds", "resourceDetailsToSend": "all", "messagesToSend": "all", "detailedMessagesToSend": "all"}},
+			{"id": "failures", "eventType": "build.complete", "publisherId": "tfs", "consumerId": "webHooks", "consumerActionId": "httpRequest",
+				"publisherInputs": {"buildStatus": "Failed", "definitionName": "app-ci", "projectId": "aaaa", "tfsSubscriptionId": "dddd"},
+				"consumerInputs": {"url": "https://receiver.example.com/failures"}}]}`)
+	})
+
+	plan := func(url string, publisherInputs map[string]string) *WebhookSubscriptionTF {
+		return &WebhookSubscriptionTF{
+			ConsumerActionId:   types.StringValue("httpRequest"),
+			ConsumerId:         types.StringValue("webHooks"),
+			ConsumerInputs:     &ConsumerInputsTF{URL: URLValue{StringValue: types.StringValue(url)}},
+			EventType:          types.StringValue("build.complete"),
+			PublisherId:        types.StringValue("tfs"),
+			PublisherInputs:    &PublisherInputsTF{ProjectId: GUIDValue{StringValue: types.StringValue("aaaa")}},
+			PublisherInputsMap: stringMapValue(publisherInputs),
+		}
+	}
+
+	id, err := findAdoptableSubscription(client, plan("https://receiver.example.com/builds", nil))
+	if err != nil || id == nil || *id != "builds" {
+		t.Errorf("expected to adopt builds despite its empty defaults, got %v (%v)", id, err)
+	}
+
+	// Inputs which are not planned are ignored, the planned ones have to match
+	id, err = findAdoptableSubscription(client, plan("https://receiver.example.com/failures", map[string]string{"buildStatus": "Failed"}))
+	if err != nil || id == nil || *id != "failures" {
+		t.Errorf("expected to adopt failures, got %v (%v)", id, err)
+	}
+
+	id, err = findAdoptableSubscription(client, plan("https://receiver.example.com/builds", map[string]string{"buildStatus": "Failed"}))
+	if err != nil || id != nil {
+		t.Errorf("expected nothing to adopt for another build status, got %v (%v)", id, err)
+	}
+}
+
+func TestImportStateDefaults(t *testing.T) {
+	ctx := context.Background()
+	s := subscriptionSchema(t)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.TrimSuffix(r.URL.Path, "/") != "/org/_apis/hooks/subscriptions/subscription" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"id": "subscription", "eventType": "git.push", "publisherId": "tfs", "consumerId": "webHooks",
+			"consumerActionId": "httpRequest", "status": "enabled", "consumerInputs": {"url": "https://receiver.example.com/hook"},
+			"publisherInputs": {"projectId": "aaaa"}}`)
+	})
+
+	resp := resource.ImportStateResponse{State: tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}}
+	(&SubscriptionResource{client: client}).ImportState(ctx, resource.ImportStateRequest{ID: "subscription"}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+
+	var data WebhookSubscriptionTF
+	if diags := resp.State.Get(ctx, &data); diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	// Attributes with schema defaults must not be null, or the first plan after the import updates them
+	local := map[string]attr.Value{
+		"adopt_existing":                         data.AdoptExisting,
+		"consumer_inputs.accept_untrusted_certs": data.ConsumerInputs.AcceptUntrustedCerts,
+		"deletion_protection":                    data.DeletionProtection,
+		"enabled":                                data.Enabled,
+		"restore_from_probation":                 data.RestoreFromProbation,
+		"verify_failure_mode":                    data.VerifyFailureMode,
+		"verify_on_apply":                        data.VerifyOnApply,
+	}
+	for name, value := range local {
+		if value.IsNull() {
+			t.Errorf("expected %s to be set to its default after import", name)
+		}
+	}
+}
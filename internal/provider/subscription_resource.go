@@ -6,14 +6,29 @@ package provider
 import (
 	"context"
 	"fmt"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
-var _ resource.Resource = &SubscriptionResource{}
+var (
+	_ resource.Resource                     = &SubscriptionResource{}
+	_ resource.ResourceWithModifyPlan       = &SubscriptionResource{}
+	_ resource.ResourceWithConfigValidators = &SubscriptionResource{}
+	_ resource.ResourceWithUpgradeState     = &SubscriptionResource{}
+)
 
 func NewSubscriptionResource() resource.Resource {
 	return &SubscriptionResource{}
@@ -32,22 +47,43 @@ func (r *SubscriptionResource) Metadata(ctx context.Context, req resource.Metada
 // Schema defines the resource schema.
 func (r *SubscriptionResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version: 1,
 		Attributes: map[string]schema.Attribute{
+			"action_description": schema.StringAttribute{
+				Computed:    true,
+				Description: "A description of the consumer action, as shown by Azure DevOps.",
+			},
+			"adopt_existing": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Whether to take over an existing subscription with the same publisher, event type, consumer, action, publisher_inputs, URL and configured free-form inputs instead of creating a duplicate, e.g. when migrating hooks created in the web UI. Publisher inputs which are not configured must not be set on the existing subscription either, free-form inputs which are not configured are ignored. The adopted subscription is updated to the configured values. Creation fails if more than one subscription matches. Defaults to false.",
+			},
 			"consumer_action_id": schema.StringAttribute{
 				Required:    true,
 				Description: "The action the consumer will perform, typically representing the type of request, such as an HTTP request.",
+				Validators:  []validator.String{consumerActionIdValidator()},
 			},
 			"consumer_id": schema.StringAttribute{
-				Required:    true,
-				Description: "Identifies the consumer of the webhook. For example, 'webHooks' to indicate that a webhook will be triggered.",
+				Required:      true,
+				Description:   "Identifies the consumer of the webhook. For example, 'webHooks' to indicate that a webhook will be triggered. Changing this forces a new subscription to be created.",
+				Validators:    []validator.String{consumerIdValidator()},
+				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
 			},
 			"consumer_inputs": schema.SingleNestedAttribute{
 				Optional:    true,
 				Description: "Inputs that are required by the consumer action, such as URL, authentication, and headers.",
 				Attributes: map[string]schema.Attribute{
 					"url": schema.StringAttribute{
+						CustomType:  URLType{},
 						Optional:    true,
-						Description: "The target URL for the webhook where the HTTP request will be sent.",
+						Description: "The target URL for the webhook where the HTTP request will be sent. A trailing slash added by Azure DevOps is not a difference.",
+					},
+					"accept_untrusted_certs": schema.BoolAttribute{
+						Optional:    true,
+						Computed:    true,
+						Default:     booldefault.StaticBool(false),
+						Description: "Whether to accept self-signed or otherwise untrusted TLS certificates of the receiver, e.g. of internal receivers with certificates of a private CA. Requires an https URL. Defaults to false.",
 					},
 					"basic_auth_username": schema.StringAttribute{
 						Optional:    true,
@@ -56,78 +92,217 @@ func (r *SubscriptionResource) Schema(ctx context.Context, req resource.SchemaRe
 					"basic_auth_password": schema.StringAttribute{
 						Optional:    true,
 						Sensitive:   true,
-						Description: "The password for basic HTTP authentication when invoking the webhook. Marked as sensitive to prevent exposure in logs.",
+						Description: "The password for basic HTTP authentication when invoking the webhook. Marked as sensitive to prevent exposure in logs. Azure DevOps never returns the password, so it is re-applied whenever the subscription was modified outside of Terraform.",
+					},
+					"headers": schema.MapAttribute{
+						ElementType: types.StringType,
+						Optional:    true,
+						Description: "HTTP headers to include in the webhook request, keyed by header name. Credentials such as Authorization, *-Key or *-Token headers belong in sensitive_headers, setting them here is warned about.",
 					},
-					"http_headers": schema.StringAttribute{
+					"sensitive_headers": schema.MapAttribute{
+						ElementType: types.StringType,
 						Optional:    true,
-						Description: "A list of HTTP headers to include in the webhook request, formatted as a comma-separated string (e.g., 'Header1:Value1,Header2:Value2').",
+						Sensitive:   true,
+						Description: "Like headers, but for secret values such as API keys. Marked as sensitive to prevent exposure in logs. Values masked by Azure DevOps are kept as configured.",
 					},
 					"resource_details_to_send": schema.StringAttribute{
 						Optional:    true,
-						Description: "Specifies the level of resource detail that will be sent to the webhook (e.g., 'minimal' or 'detailed').",
+						Description: "Specifies the level of resource detail that will be sent to the webhook, one of 'all', 'minimal' or 'none'.",
+						Validators:  []validator.String{resourceDetailsValidator()},
 					},
 					"messages_to_send": schema.StringAttribute{
 						Optional:    true,
 						Description: "Defines which messages, if any, will be sent to the webhook. Typically 'none' to send no messages.",
+						Validators:  []validator.String{messagesValidator()},
 					},
 					"detailed_messages_to_send": schema.StringAttribute{
 						Optional:    true,
 						Description: "Defines whether detailed messages should be sent to the webhook, usually 'none'.",
+						Validators:  []validator.String{messagesValidator()},
 					},
 				},
 			},
+			"consumer_inputs_map": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Additional consumer inputs keyed by their Azure DevOps input id, for consumers whose inputs are not covered by consumer_inputs (e.g. 'connectionString' and 'queueName' for 'azureServiceBus'). Inputs of consumer_inputs must not be repeated here. Only the configured inputs are tracked, defaults Azure DevOps adds for other inputs are ignored. Inputs which Azure DevOps treats as confidential belong in sensitive_consumer_inputs_map, setting them here is warned about.",
+			},
+			"created_by": schema.StringAttribute{
+				Computed:      true,
+				Description:   "The unique name of the identity which created the subscription, or its display name if it has none.",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"created_date": schema.StringAttribute{
+				Computed:      true,
+				Description:   "The date the subscription was created.",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"deletion_protection": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Whether to prevent the subscription from being destroyed or replaced. Destroying or replacing a protected subscription fails until deletion_protection has been set to false in a separate apply. Defaults to false.",
+			},
+			"enabled": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Whether the subscription is enabled. Set to false to pause deliveries without deleting the subscription. Defaults to true.",
+			},
+			"event_description": schema.StringAttribute{
+				Computed:    true,
+				Description: "A description of the event filter, as shown by Azure DevOps.",
+			},
 			"event_type": schema.StringAttribute{
-				Required:    true,
-				Description: "The type of event that triggers the webhook, such as 'git.push' for a Git push event.",
+				Required:      true,
+				Description:   "The type of event that triggers the webhook, such as 'git.push' for a Git push event. Changing this forces a new subscription to be created.",
+				Validators:    []validator.String{eventTypeValidator()},
+				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
 			},
 			"id": schema.StringAttribute{
+				Optional:      true,
+				Computed:      true,
+				Description:   "The unique identifier of the webhook subscription. This is usually computed by the system.",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"last_probation_retry_date": schema.StringAttribute{
+				Computed:    true,
+				Description: "The date of the last delivery retried while the subscription was on probation.",
+			},
+			"modified_by": schema.StringAttribute{
+				Computed:    true,
+				Description: "The unique name of the identity which last modified the subscription, or its display name if it has none.",
+			},
+			"modified_date": schema.StringAttribute{
+				Computed:    true,
+				Description: "The date the subscription was last modified.",
+			},
+			"organization": schema.StringAttribute{
 				Optional:    true,
 				Computed:    true,
-				Description: "The unique identifier of the webhook subscription. This is usually computed by the system.",
+				Description: "The Azure DevOps organization the subscription belongs to. Defaults to the organization of the provider. Changing this forces a new subscription to be created.",
+			},
+			"probation_retries": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The number of failed deliveries retried while the subscription was on probation.",
 			},
 			"publisher_id": schema.StringAttribute{
-				Required:    true,
-				Description: "The ID of the publisher that initiates the event (e.g., 'tfs' for Azure DevOps or Team Foundation Server).",
+				Required:      true,
+				Description:   "The ID of the publisher that initiates the event (e.g., 'tfs' for Azure DevOps or Team Foundation Server). Changing this forces a new subscription to be created.",
+				Validators:    []validator.String{publisherIdValidator()},
+				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
 			},
 			"publisher_inputs": schema.SingleNestedAttribute{
 				Optional:    true,
 				Description: "Details about the publisher and the specific resources related to the event.",
 				Attributes: map[string]schema.Attribute{
 					"repository": schema.StringAttribute{
+						CustomType:  GUIDType{},
+						Optional:    true,
+						Computed:    true,
+						Description: "The ID of the repository from which the event (such as a push) originates. Resolved from repository_name if that is set instead. Compared case-insensitively.",
+					},
+					"repository_name": schema.StringAttribute{
 						Optional:    true,
-						Description: "The repository from which the event (such as a push) originates.",
+						Description: "The name of the repository from which the event originates, resolved to its ID when planning. Conflicts with repository and requires project_id or project_name. A repository renamed in Azure DevOps is reported with its new name when planning.",
 					},
 					"branch": schema.StringAttribute{
+						CustomType:  BranchType{},
 						Optional:    true,
-						Description: "The branch in the repository where the event occurred.",
+						Description: "The branch in the repository where the event occurred, with or without the refs/heads/ prefix.",
 					},
 					"pushed_by": schema.StringAttribute{
 						Optional:    true,
 						Description: "The user who pushed the changes in a Git push event.",
 					},
 					"project_id": schema.StringAttribute{
+						CustomType:  GUIDType{},
 						Optional:    true,
-						Description: "The unique ID of the project associated with the event.",
+						Computed:    true,
+						Description: "The unique ID of the project associated with the event. Resolved from project_name if that is set instead. Compared case-insensitively. Changing this forces a new subscription to be created.",
 					},
-					"tfs_subscription_id": schema.StringAttribute{
+					"project_name": schema.StringAttribute{
 						Optional:    true,
-						Computed:    true,
-						Description: "The subscription ID from TFS or Azure DevOps that identifies this specific webhook subscription.",
+						Description: "The name of the project associated with the event, resolved to its ID when planning. Conflicts with project_id. A project renamed in Azure DevOps is reported with its new name when planning.",
+					},
+					"tfs_subscription_id": schema.StringAttribute{
+						CustomType:    GUIDType{},
+						Optional:      true,
+						Computed:      true,
+						Description:   "The subscription ID from TFS or Azure DevOps that identifies this specific webhook subscription.",
+						PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 					},
 				},
 			},
+			"publisher_inputs_map": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Additional publisher inputs keyed by their Azure DevOps input id, used as event filters not covered by publisher_inputs (e.g. 'definitionName' and 'buildStatus' for 'build.complete' or 'areaPath' and 'workItemType' for work item events). Inputs of publisher_inputs must not be repeated here. Only the configured inputs are tracked, defaults Azure DevOps adds for other inputs are ignored.",
+			},
 			"resource_version": schema.StringAttribute{
 				Optional:    true,
-				Description: "The version of the resource triggering the webhook event, typically set to '1.0' or another version string.",
+				Computed:    true,
+				Description: "The version of the event payload sent to the consumer, e.g. '1.0' or '5.1-preview.1'. Must be one of the versions supported by the event type. Defaults to the latest released version supported by the event type, or its latest preview if it has no release, when the subscription is created and is kept afterwards.",
+			},
+			"restore_from_probation": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Whether to re-enable the subscription when Azure DevOps put it on probation or disabled it after failed deliveries. If true, a suspended subscription shows up as drift and the next apply re-enables it. Defaults to false.",
 			},
 			"scope": schema.Int64Attribute{
 				Optional:    true,
 				Description: "Defines the scope of the webhook event. This is often an integer representing a specific scope or context.",
 			},
+			"secrets_version": schema.StringAttribute{
+				Optional:    true,
+				Description: "An arbitrary value such as a date or counter. Every update sends all secret inputs (basic_auth_password, sensitive_headers and sensitive_consumer_inputs_map) to Azure DevOps, changing this value forces an update when nothing else changed, e.g. to re-send unchanged secrets after the receiving side was re-provisioned. The subscription is not recreated.",
+			},
+			"sensitive_consumer_inputs_map": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Like consumer_inputs_map, but for secret inputs such as connection strings or tokens. Azure DevOps masks secrets in its responses, so the configured values are kept in state.",
+			},
+			"status": schema.StringAttribute{
+				Computed:    true,
+				Description: "The status of the subscription as reported by Azure DevOps: 'enabled', 'disabledByUser', or one of 'onProbation', 'disabledBySystem' and 'disabledByInactiveIdentity' when Azure DevOps suspended it.",
+			},
+			"verify_failure_mode": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(verifyFailureModeError),
+				Description: "How a failed test notification of verify_on_apply is reported, 'error' to fail the apply or 'warning' to only warn. A subscription failing verification on create is tainted in error mode. Defaults to 'error'.",
+				Validators:  []validator.String{verifyFailureModeValidator()},
+			},
+			"verify_on_apply": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Whether to send a test notification through the consumer after every create and update and check that it was delivered. Defaults to false.",
+			},
+			"url": schema.StringAttribute{
+				Computed:      true,
+				Description:   "The REST API URL of the subscription.",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
 		},
 	}
 }
 
+// ConfigValidators returns the validators enforcing rules across attributes.
+func (r *SubscriptionResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		catalogConfigValidator{},
+		webhookURLConfigValidator{},
+		untrustedCertsConfigValidator{},
+		inputMapsConfigValidator{},
+		headersConfigValidator{},
+		secretHeadersConfigValidator{},
+		publisherNamesConfigValidator{},
+	}
+}
+
 // Configure sets up the client for the resource.
 func (r *SubscriptionResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Add a nil check when handling ProviderData because Terraform
@@ -149,6 +324,438 @@ func (r *SubscriptionResource) Configure(ctx context.Context, req resource.Confi
 	r.client = client
 }
 
+// clientFor returns the client for the organization of the resource, falling back to
+// the provider organization when none is set.
+func (r *SubscriptionResource) clientFor(organization types.String) *Client {
+	return r.client.WithOrganization(organization.ValueString())
+}
+
+// ModifyPlan defaults the organization to the one of the provider, resolves project and repository
+// names, enforces deletion protection, selects the resource version and validates the planned inputs
+// against the metadata Azure DevOps publishes for the event type and consumer action.
+func (r *SubscriptionResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		if deletionProtected(ctx, req.State, &resp.Diagnostics) {
+			addDeletionProtectionError(&resp.Diagnostics)
+		}
+		return
+	}
+
+	// Deletion protection only depends on state and plan, so it also holds before the provider is configured
+	protected := !req.State.Raw.IsNull() && deletionProtected(ctx, req.State, &resp.Diagnostics)
+	if protected {
+		if replaced := replacedAttributes(ctx, req.State, req.Plan); len(replaced) > 0 {
+			addReplacementProtectionError(replaced, &resp.Diagnostics)
+			return
+		}
+	}
+
+	// Nothing to plan before the provider has been configured
+	if r.client == nil {
+		return
+	}
+
+	r.planOrganization(ctx, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.planPublisherInputs(ctx, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Moves to another organization or project are only known once planned
+	if protected && len(resp.RequiresReplace) > 0 {
+		addReplacementProtectionError(resp.RequiresReplace, &resp.Diagnostics)
+		return
+	}
+
+	r.planResourceVersion(ctx, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The model cannot hold input blocks which are unknown as a whole, their inputs are validated on apply
+	if inputsUnknown(ctx, resp.Plan, &resp.Diagnostics) {
+		return
+	}
+
+	var plan WebhookSubscriptionTF
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	validatePlanMetadata(ctx, r.clientFor(plan.Organization), &plan, &resp.Diagnostics)
+}
+
+// inputsUnknown reports whether consumer_inputs or publisher_inputs is unknown as a whole, e.g. when
+// it is set conditionally on a value which is only known on apply.
+func inputsUnknown(ctx context.Context, plan tfsdk.Plan, diags *diag.Diagnostics) bool {
+	var consumerInputs, publisherInputs types.Object
+	diags.Append(plan.GetAttribute(ctx, path.Root("consumer_inputs"), &consumerInputs)...)
+	diags.Append(plan.GetAttribute(ctx, path.Root("publisher_inputs"), &publisherInputs)...)
+	return consumerInputs.IsUnknown() || publisherInputs.IsUnknown()
+}
+
+// replacedAttributes returns the attributes whose change replaces the subscription. Attribute plan
+// modifiers run before ModifyPlan and their replacements are not passed on to it, so they are
+// derived from state and plan.
+func replacedAttributes(ctx context.Context, state tfsdk.State, plan tfsdk.Plan) path.Paths {
+	var replaced path.Paths
+	for _, name := range []string{"consumer_id", "event_type", "publisher_id"} {
+		var prior, planned types.String
+		if state.GetAttribute(ctx, path.Root(name), &prior).HasError() || plan.GetAttribute(ctx, path.Root(name), &planned).HasError() {
+			continue
+		}
+		if !prior.Equal(planned) {
+			replaced = append(replaced, path.Root(name))
+		}
+	}
+	return replaced
+}
+
+// deletionProtected reports whether the subscription in state has deletion protection enabled.
+// Protection has to be lifted in a separate apply, so only the state is consulted.
+func deletionProtected(ctx context.Context, state tfsdk.State, diags *diag.Diagnostics) bool {
+	var protected types.Bool
+	diags.Append(state.GetAttribute(ctx, path.Root("deletion_protection"), &protected)...)
+	return protected.ValueBool()
+}
+
+func addDeletionProtectionError(diags *diag.Diagnostics) {
+	diags.AddError(
+		"Subscription is protected from deletion",
+		"The subscription has deletion_protection enabled. Set deletion_protection to false and apply that change before destroying it.",
+	)
+}
+
+func addReplacementProtectionError(replaced path.Paths, diags *diag.Diagnostics) {
+	names := make([]string, len(replaced))
+	for i, p := range replaced {
+		names[i] = p.String()
+	}
+	diags.AddAttributeError(
+		replaced[0],
+		"Subscription is protected from replacement",
+		fmt.Sprintf("Changing %s replaces the subscription, which has deletion_protection enabled. Set deletion_protection to false and apply that change before replacing it.", strings.Join(names, ", ")),
+	)
+}
+
+// planOrganization fills in the provider organization when the resource does not override it and
+// forces a replacement when the subscription moves to another organization.
+func (r *SubscriptionResource) planOrganization(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var organization types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("organization"), &organization)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if organization.IsNull() {
+		organization = types.StringValue(r.client.Organization)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("organization"), organization)...)
+	}
+
+	if req.State.Raw.IsNull() || organization.IsUnknown() {
+		return
+	}
+
+	var stateOrganization types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("organization"), &stateOrganization)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// States written before the attribute existed have no organization and belong to the provider organization
+	if !stateOrganization.IsNull() && !stateOrganization.Equal(organization) {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("organization"))
+	}
+}
+
+// planResourceVersion validates a configured resource version against the versions supported by the
+// event type. Without one, the version in state is kept unless the event type changes, otherwise the
+// latest supported version is planned, falling back to defaultResourceVersion if the event type
+// metadata cannot be retrieved.
+func (r *SubscriptionResource) planResourceVersion(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var configured, organization, publisherId, eventType types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("resource_version"), &configured)...)
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("organization"), &organization)...)
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("publisher_id"), &publisherId)...)
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("event_type"), &eventType)...)
+	if resp.Diagnostics.HasError() || configured.IsUnknown() || publisherId.IsUnknown() || eventType.IsUnknown() {
+		return
+	}
+
+	if configured.IsNull() && !req.State.Raw.IsNull() {
+		var state WebhookSubscriptionTF
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if !state.ResourceVersion.IsNull() && state.PublisherId.Equal(publisherId) && state.EventType.Equal(eventType) {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("resource_version"), state.ResourceVersion)...)
+			return
+		}
+	}
+
+	var supported []string
+	eventTypes, err := r.clientFor(organization).GetEventTypes(publisherId.ValueString())
+	if err != nil {
+		tflog.Warn(ctx, "Skipping resource version selection: "+err.Error())
+	} else if metadata := findEventType(eventTypes, eventType.ValueString()); metadata != nil {
+		supported = metadata.SupportedResourceVersions
+	}
+
+	if !configured.IsNull() {
+		resp.Diagnostics.Append(validateResourceVersion(configured.ValueString(), eventType.ValueString(), supported)...)
+		return
+	}
+
+	resourceVersion := defaultResourceVersion
+	if latest := latestResourceVersion(supported); latest != "" {
+		resourceVersion = latest
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("resource_version"), types.StringValue(resourceVersion))...)
+}
+
+// validateResourceVersion checks a configured resource version against the versions supported by
+// the event type. Event types without metadata accept any version.
+func validateResourceVersion(version, eventType string, supported []string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if len(supported) == 0 {
+		return diags
+	}
+
+	for _, s := range supported {
+		if s == version {
+			return diags
+		}
+	}
+
+	diags.AddAttributeError(
+		path.Root("resource_version"),
+		"Unsupported resource version",
+		fmt.Sprintf("The event type %q does not support resource version %q. Supported versions: %s.", eventType, version, strings.Join(supported, ", ")),
+	)
+	return diags
+}
+
+// planPublisherInputs resolves the project and repository names of the publisher inputs to their ids
+// and requires a replacement if the subscription is moved to another project. The ids are computed,
+// so this cannot be left to attribute plan modifiers, which only see them as unknown.
+func (r *SubscriptionResource) planPublisherInputs(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var organization types.String
+	var configInputs, planInputs, stateInputs types.Object
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("organization"), &organization)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("publisher_inputs"), &configInputs)...)
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("publisher_inputs"), &planInputs)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("publisher_inputs"), &stateInputs)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	config := publisherInputsFrom(ctx, configInputs, &resp.Diagnostics)
+	plan := publisherInputsFrom(ctx, planInputs, &resp.Diagnostics)
+	state := publisherInputsFrom(ctx, stateInputs, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	planProject := types.StringNull()
+	switch {
+	case planInputs.IsUnknown():
+		planProject = types.StringUnknown()
+	case plan != nil && config != nil:
+		resolvePublisherNames(r.clientFor(organization), config, state, plan, &resp.Diagnostics)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("publisher_inputs"), plan)...)
+		planProject = plan.ProjectId.StringValue
+	}
+
+	if req.State.Raw.IsNull() || resp.Diagnostics.HasError() {
+		return
+	}
+
+	stateProject := types.StringNull()
+	if state != nil {
+		stateProject = state.ProjectId.StringValue
+	}
+	if projectChanged(stateProject, planProject) {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("publisher_inputs").AtName("project_id"))
+	}
+}
+
+// publisherInputsFrom converts a publisher_inputs object into its model, or returns nil if it is null
+// or unknown.
+func publisherInputsFrom(ctx context.Context, object types.Object, diags *diag.Diagnostics) *PublisherInputsTF {
+	if object.IsNull() || object.IsUnknown() {
+		return nil
+	}
+
+	var inputs PublisherInputsTF
+	diags.Append(object.As(ctx, &inputs, basetypes.ObjectAsOptions{})...)
+	return &inputs
+}
+
+// resolvePublisherNames sets the project and repository ids of plan, resolving configured names.
+// Without names the configured ids are planned as they are. state holds the names and ids planned
+// before, if any, to tell a renamed project or repository from a misspelled one.
+func resolvePublisherNames(client *Client, config, state, plan *PublisherInputsTF, diags *diag.Diagnostics) {
+	projectPath := path.Root("publisher_inputs").AtName("project_name")
+	switch {
+	case config.ProjectName.IsNull():
+		plan.ProjectId = config.ProjectId
+	case config.ProjectName.IsUnknown():
+		plan.ProjectId = GUIDValue{StringValue: types.StringUnknown()}
+	default:
+		name := config.ProjectName.ValueString()
+		project, err := client.GetProjectGuid(name)
+		if err != nil {
+			var current func() (*IdResponse, error)
+			if state != nil && strings.EqualFold(state.ProjectName.ValueString(), name) && !state.ProjectId.IsNull() {
+				current = func() (*IdResponse, error) { return client.GetProjectGuid(state.ProjectId.ValueString()) }
+			}
+			addNameResolutionError(projectPath, "project", name, err, current, diags)
+			return
+		}
+		plan.ProjectId = GUIDValue{StringValue: types.StringValue(project.ID)}
+	}
+
+	repositoryPath := path.Root("publisher_inputs").AtName("repository_name")
+	switch {
+	case config.RepositoryName.IsNull():
+		plan.RepositoryId = config.RepositoryId
+	case config.RepositoryName.IsUnknown() || plan.ProjectId.IsUnknown():
+		plan.RepositoryId = GUIDValue{StringValue: types.StringUnknown()}
+	default:
+		name := config.RepositoryName.ValueString()
+		repository, err := client.GetRepositoryGuid(plan.ProjectId.ValueString(), name)
+		if err != nil {
+			var current func() (*IdResponse, error)
+			if state != nil && strings.EqualFold(state.RepositoryName.ValueString(), name) && !state.RepositoryId.IsNull() && !state.ProjectId.IsNull() {
+				current = func() (*IdResponse, error) {
+					return client.GetRepositoryGuid(state.ProjectId.ValueString(), state.RepositoryId.ValueString())
+				}
+			}
+			addNameResolutionError(repositoryPath, "repository", name, err, current, diags)
+			return
+		}
+		plan.RepositoryId = GUIDValue{StringValue: types.StringValue(repository.ID)}
+	}
+}
+
+// addNameResolutionError reports a configured name which cannot be resolved. If current looks up the
+// id the name was resolved to before, a name which no longer exists is reported as renamed, along
+// with the new name to configure.
+func addNameResolutionError(attribute path.Path, kind, name string, err error, current func() (*IdResponse, error), diags *diag.Diagnostics) {
+	if !IsNotFound(err) {
+		diags.AddAttributeError(attribute, "Failed to resolve "+kind, fmt.Sprintf("Failed to resolve %s %q: %s", kind, name, err))
+		return
+	}
+
+	if current != nil {
+		if renamed, err := current(); err == nil && !strings.EqualFold(renamed.Name, name) {
+			diags.AddAttributeError(attribute, "Renamed "+kind,
+				fmt.Sprintf("The %s %q was renamed to %q outside of Terraform. Set %s to the new name.", kind, name, renamed.Name, attribute))
+			return
+		}
+	}
+
+	diags.AddAttributeError(attribute, "Unknown "+kind,
+		fmt.Sprintf("The %s %q does not exist. Check %s, it may have been renamed or deleted outside of Terraform.", kind, name, attribute))
+}
+
+// refreshPublisherNames updates the project and repository names of data if their ids differ from
+// prior, i.e. the subscription was moved outside of Terraform, so the move shows up as a difference
+// to the configuration. Names of deleted projects or repositories are removed. Renames keep the ids
+// and are reported when planning.
+func refreshPublisherNames(client *Client, prior, data *PublisherInputsTF, diags *diag.Diagnostics) {
+	if !data.ProjectName.IsNull() && !data.ProjectId.IsNull() && !strings.EqualFold(prior.ProjectId.ValueString(), data.ProjectId.ValueString()) {
+		project, err := client.GetProjectGuid(data.ProjectId.ValueString())
+		switch {
+		case IsNotFound(err):
+			data.ProjectName = types.StringNull()
+		case err != nil:
+			diags.AddError("Client Error", fmt.Sprintf("Failed to get project: %s", err))
+		default:
+			data.ProjectName = types.StringValue(project.Name)
+		}
+	}
+
+	if !data.RepositoryName.IsNull() && !data.RepositoryId.IsNull() && !data.ProjectId.IsNull() && !strings.EqualFold(prior.RepositoryId.ValueString(), data.RepositoryId.ValueString()) {
+		repository, err := client.GetRepositoryGuid(data.ProjectId.ValueString(), data.RepositoryId.ValueString())
+		switch {
+		case IsNotFound(err):
+			data.RepositoryName = types.StringNull()
+		case err != nil:
+			diags.AddError("Client Error", fmt.Sprintf("Failed to get repository: %s", err))
+		default:
+			data.RepositoryName = types.StringValue(repository.Name)
+		}
+	}
+}
+
+// projectChanged reports whether a subscription is moved to another project or its project scope is
+// added or removed. Project ids are GUIDs, so a change of case only is not a move.
+func projectChanged(state, plan types.String) bool {
+	if state.Equal(plan) {
+		return false
+	}
+	return plan.IsUnknown() || !strings.EqualFold(state.ValueString(), plan.ValueString())
+}
+
+// validatePlanMetadata validates the planned publisher and consumer inputs against the input
+// descriptors of the event type and consumer action. Metadata which cannot be retrieved, for
+// example because of missing permissions, is skipped.
+func validatePlanMetadata(ctx context.Context, client *Client, plan *WebhookSubscriptionTF, diags *diag.Diagnostics) {
+	// Inputs of unknown maps cannot be told apart from missing ones, such inputs are validated on apply
+	if !plan.PublisherId.IsUnknown() && !plan.EventType.IsUnknown() && !plan.PublisherInputsMap.IsUnknown() {
+		eventTypes, err := client.GetEventTypes(plan.PublisherId.ValueString())
+		if err != nil {
+			tflog.Warn(ctx, "Skipping publisher input validation: "+err.Error())
+		} else if eventType := findEventType(eventTypes, plan.EventType.ValueString()); eventType != nil {
+			diags.Append(validateInputs(eventType.InputDescriptors, plan.publisherInputsByID(), path.Root("publisher_inputs"))...)
+		}
+	}
+
+	if !plan.ConsumerId.IsUnknown() && !plan.ConsumerActionId.IsUnknown() && !plan.ConsumerInputsMap.IsUnknown() && !plan.SensitiveConsumerInputsMap.IsUnknown() {
+		actions, err := client.GetConsumerActions(plan.ConsumerId.ValueString())
+		if err != nil {
+			tflog.Warn(ctx, "Skipping consumer input validation: "+err.Error())
+		} else if action := findConsumerAction(actions, plan.ConsumerActionId.ValueString()); action != nil {
+			if !plan.EventType.IsUnknown() && !action.supportsEventType(plan.EventType.ValueString()) {
+				diags.AddAttributeError(
+					path.Root("event_type"),
+					"Unsupported event type",
+					fmt.Sprintf("The action %q of consumer %q cannot be triggered by %q. Supported event types: %s.",
+						action.ID, action.ConsumerId, plan.EventType.ValueString(), strings.Join(action.SupportedEventTypes, ", ")),
+				)
+			}
+			diags.Append(validateInputs(action.InputDescriptors, plan.consumerInputsByID(), path.Root("consumer_inputs"))...)
+			diags.Append(confidentialInputWarnings(action.InputDescriptors, plan.ConsumerInputsMap)...)
+		}
+	}
+}
+
+// confidentialInputWarnings warns about inputs which Azure DevOps treats as secrets but which are set
+// in consumer_inputs_map, whose values are shown in plan output and logs.
+func confidentialInputWarnings(descriptors []InputDescriptor, inputs types.Map) diag.Diagnostics {
+	var diags diag.Diagnostics
+	plain := inputs.Elements()
+	for _, descriptor := range descriptors {
+		if _, ok := plain[descriptor.ID]; ok && descriptor.IsConfidential {
+			diags.AddAttributeWarning(
+				path.Root("consumer_inputs_map").AtMapKey(descriptor.ID),
+				"Confidential input in consumer_inputs_map",
+				fmt.Sprintf("The input %q (%s) is confidential, but consumer_inputs_map is shown in plan output and logs. Move it to sensitive_consumer_inputs_map.", descriptor.ID, descriptor.Name),
+			)
+		}
+	}
+	return diags
+}
+
 // Create creates a new Azure DevOps webhook using the provided parameters.
 func (r *SubscriptionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data WebhookSubscriptionTF
@@ -159,16 +766,30 @@ func (r *SubscriptionResource) Create(ctx context.Context, req resource.CreateRe
 		return
 	}
 
-	// Save password before request
-	var basicAuthPassword *string = nil
-	if data.ConsumerInputs != nil && !data.ConsumerInputs.BasicAuthPassword.IsNull() {
-		basicAuthPassword = data.ConsumerInputs.BasicAuthPassword.ValueStringPointer()
-	}
+	// Save secrets before request
+	plan := data
 
+	client := r.clientFor(data.Organization)
 	requestData := ConvertToJSONModel(&data)
 
+	// Updating an identical subscription instead of creating another one takes it over
+	if data.AdoptExisting.ValueBool() {
+		existingID, err := findAdoptableSubscription(client, &data)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Client Error",
+				fmt.Sprintf("Failed to look up existing webhooks to adopt: %s", err),
+			)
+			return
+		}
+		if existingID != nil {
+			tflog.Info(ctx, "Adopting existing Azure DevOps Webhook", map[string]interface{}{"id": *existingID})
+			requestData.ID = existingID
+		}
+	}
+
 	// Create the webhook using the client and pass the project_id
-	webhookResponse, err := r.client.CreateOrUpdateWebhook(
+	webhookResponse, err := client.CreateOrUpdateWebhook(
 		requestData,
 	)
 	if err != nil {
@@ -181,17 +802,26 @@ func (r *SubscriptionResource) Create(ctx context.Context, req resource.CreateRe
 
 	// Set the webhook ID after creation
 	data = *ConvertToTFModel(webhookResponse)
+	data.Organization = types.StringValue(client.Organization)
 
-	// The API response replaces the password with "****" however, to compare the state correctly we need to keep the original pw
-	if basicAuthPassword != nil && data.ConsumerInputs != nil {
-		data.ConsumerInputs.BasicAuthPassword = types.StringPointerValue(basicAuthPassword)
-	}
+	// The API response replaces secrets with "****" however, to compare the state correctly we need to keep the original values
+	restoreSecrets(&plan, &data)
+	keepLocalAttributes(&plan, &data)
+
+	// Remember the password sent to notice when it may have been changed outside of Terraform
+	resp.Diagnostics.Append(storePasswordPrivateState(ctx, resp.Private, requestData.ConsumerInputs.BasicAuthPassword, webhookResponse.ModifiedDate)...)
 
 	// Log creation
 	tflog.Trace(ctx, "Created Azure DevOps Webhook")
 
 	// Save the data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Verify after saving the state, a failed verification taints the created subscription
+	verifyOnApply(ctx, client, &plan, webhookResponse.ID, requestData, &resp.Diagnostics)
 }
 
 // Read reads the current state of the Azure DevOps webhook resource.
@@ -204,14 +834,19 @@ func (r *SubscriptionResource) Read(ctx context.Context, req resource.ReadReques
 		return
 	}
 
-	// Save password before request
-	var basicAuthPassword *string = nil
-	if data.ConsumerInputs != nil && !data.ConsumerInputs.BasicAuthPassword.IsNull() {
-		basicAuthPassword = data.ConsumerInputs.BasicAuthPassword.ValueStringPointer()
-	}
+	// Save secrets before request
+	prior := data
+
+	client := r.clientFor(data.Organization)
 
 	// Get the webhook using the client and pass the project_id
-	webhookResponse, err := r.client.GetWebhook(data.ID.ValueString())
+	webhookResponse, err := client.GetWebhook(data.ID.ValueString())
+	if IsNotFound(err) {
+		// The subscription was deleted outside of Terraform, drop it from state so it gets planned for creation
+		tflog.Warn(ctx, "Azure DevOps Webhook not found, removing it from state", map[string]interface{}{"id": data.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Client Error",
@@ -222,10 +857,29 @@ func (r *SubscriptionResource) Read(ctx context.Context, req resource.ReadReques
 
 	// Update the model with the current state of the webhook
 	data = *ConvertToTFModel(webhookResponse)
+	data.Organization = types.StringValue(client.Organization)
 
-	// The API response replaces the password with "****" however, to compare the state correctly we need to keep the original pw
-	if basicAuthPassword != nil && data.ConsumerInputs != nil {
-		data.ConsumerInputs.BasicAuthPassword = types.StringPointerValue(basicAuthPassword)
+	// The password is never returned, if the subscription was modified outside of Terraform it may have been changed
+	// as well. Keep the masked value then, so the next plan re-applies the configured password.
+	if prior.ConsumerInputs != nil && !prior.ConsumerInputs.BasicAuthPassword.IsNull() {
+		drifted, diags := passwordDrifted(ctx, req.Private, prior.ConsumerInputs.BasicAuthPassword.ValueString(), webhookResponse.ModifiedDate)
+		resp.Diagnostics.Append(diags...)
+		if drifted {
+			tflog.Info(ctx, "Azure DevOps Webhook was modified outside of Terraform, basic auth password will be re-applied")
+			priorInputs := *prior.ConsumerInputs
+			priorInputs.BasicAuthPassword = types.StringNull()
+			prior.ConsumerInputs = &priorInputs
+		}
+	}
+
+	// The API response replaces secrets with "****" however, to compare the state correctly we need to keep the original values
+	restoreSecrets(&prior, &data)
+	keepLocalAttributes(&prior, &data)
+	surfaceDegradedStatus(&data)
+
+	// Subscriptions moved to another project or repository show up as a difference to the configured names
+	if prior.PublisherInputs != nil && data.PublisherInputs != nil {
+		refreshPublisherNames(client, prior.PublisherInputs, data.PublisherInputs, &resp.Diagnostics)
 	}
 
 	// Save the updated data into Terraform state
@@ -249,21 +903,40 @@ func (r *SubscriptionResource) Update(ctx context.Context, req resource.UpdateRe
 		return
 	}
 
-	// Save password before request
-	var basicAuthPassword *string = nil
-	if planData.ConsumerInputs != nil && !planData.ConsumerInputs.BasicAuthPassword.IsNull() {
-		basicAuthPassword = planData.ConsumerInputs.BasicAuthPassword.ValueStringPointer()
-	}
-
 	// Get the webhook ID from the current state
 	planData.ID = stateData.ID
 	// Log the webhook ID to verify it's being retrieved from the state correctly
 	tflog.Info(ctx, "Webhook ID from state: "+stateData.ID.ValueString())
 
+	client := r.clientFor(planData.Organization)
+
+	// All inputs are sent with every update, a new secrets_version merely forces an update when nothing else changed
+	if !planData.SecretsVersion.Equal(stateData.SecretsVersion) {
+		tflog.Info(ctx, "Secrets version changed, re-sending secret inputs of Azure DevOps Webhook")
+	}
+
 	requestData := ConvertToJSONModel(&planData)
 
+	// Sending "enabled" lifts a probation or suspension by Azure DevOps, only do so if restoring was asked for
+	if planData.Enabled.ValueBool() && isDegradedStatus(stateData.Status.ValueString()) {
+		if planData.RestoreFromProbation.ValueBool() {
+			current, err := client.GetWebhook(stateData.ID.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Client Error",
+					fmt.Sprintf("Failed to get webhook: %s", err),
+				)
+				return
+			}
+			tflog.Info(ctx, "Restoring suspended Azure DevOps Webhook", map[string]interface{}{"status": stateData.Status.ValueString()})
+			resp.Diagnostics.AddWarning("Subscription restored from probation", probationDetail(current))
+		} else {
+			requestData.Status = stateData.Status.ValueStringPointer()
+		}
+	}
+
 	// Use the planData values for the updated webhook details
-	webhookResponse, err := r.client.CreateOrUpdateWebhook(requestData)
+	webhookResponse, err := client.CreateOrUpdateWebhook(requestData)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Client Error",
@@ -274,14 +947,49 @@ func (r *SubscriptionResource) Update(ctx context.Context, req resource.UpdateRe
 
 	// Set the updated values (from the response) to planData
 	updatedData := ConvertToTFModel(webhookResponse)
+	updatedData.Organization = types.StringValue(client.Organization)
 
-	// The API response replaces the password with "****" however, to compare the state correctly we need to keep the original pw
-	if basicAuthPassword != nil && updatedData.ConsumerInputs != nil {
-		updatedData.ConsumerInputs.BasicAuthPassword = types.StringPointerValue(basicAuthPassword)
-	}
+	// The API response replaces secrets with "****" however, to compare the state correctly we need to keep the original values
+	restoreSecrets(&planData, updatedData)
+	keepLocalAttributes(&planData, updatedData)
+
+	// Remember the password sent to notice when it may have been changed outside of Terraform
+	resp.Diagnostics.Append(storePasswordPrivateState(ctx, resp.Private, requestData.ConsumerInputs.BasicAuthPassword, webhookResponse.ModifiedDate)...)
 
 	// Save the updated data into Terraform state (from planData which now holds updated values)
 	resp.Diagnostics.Append(resp.State.Set(ctx, updatedData)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	verifyOnApply(ctx, client, &planData, webhookResponse.ID, requestData, &resp.Diagnostics)
+}
+
+// verifyOnApply sends a test notification through the consumer if verify_on_apply is set and
+// reports a failed delivery according to verify_failure_mode.
+func verifyOnApply(ctx context.Context, client *Client, plan *WebhookSubscriptionTF, subscriptionId *string, subscription *WebhookSubscription, diags *diag.Diagnostics) {
+	if !plan.VerifyOnApply.ValueBool() || subscriptionId == nil {
+		return
+	}
+
+	tflog.Debug(ctx, "Sending test notification for Azure DevOps Webhook", map[string]interface{}{"id": *subscriptionId})
+
+	var detail string
+	notification, err := verifyDelivery(ctx, client, *subscriptionId, subscription)
+	if err != nil {
+		detail = fmt.Sprintf("Failed to verify the subscription: %s", err)
+	} else {
+		detail = deliveryFailure(notification)
+	}
+
+	switch {
+	case detail == "":
+		return
+	case plan.VerifyFailureMode.ValueString() == verifyFailureModeWarning:
+		diags.AddWarning("Test notification failed", detail)
+	default:
+		diags.AddError("Test notification failed", detail)
+	}
 }
 
 // Delete deletes an existing Azure DevOps webhook.
@@ -294,8 +1002,19 @@ func (r *SubscriptionResource) Delete(ctx context.Context, req resource.DeleteRe
 		return
 	}
 
+	// Deletion protection is checked when planning already, this guards against plans made without it
+	if data.DeletionProtection.ValueBool() {
+		addDeletionProtectionError(&resp.Diagnostics)
+		return
+	}
+
 	// Delete the webhook using the client and pass the project_id
-	err := r.client.DeleteWebhook(data.ID.ValueString())
+	err := r.clientFor(data.Organization).DeleteWebhook(data.ID.ValueString())
+	if IsNotFound(err) {
+		// Already gone, which is what we wanted
+		tflog.Warn(ctx, "Azure DevOps Webhook already deleted", map[string]interface{}{"id": data.ID.ValueString()})
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Client Error",
@@ -307,23 +1026,3 @@ func (r *SubscriptionResource) Delete(ctx context.Context, req resource.DeleteRe
 	// Log the deletion
 	tflog.Trace(ctx, "Deleted Azure DevOps Webhook")
 }
-
-func (r *SubscriptionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve the webhook ID from the request
-	webhookID := req.ID
-
-	// Use the client to fetch the webhook details using the ID
-	webhookResponse, err := r.client.GetWebhook(webhookID)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Client Error",
-			fmt.Sprintf("Failed to get webhook: %s", err),
-		)
-		return
-	}
-
-	// Map the response to the model
-	var data = *ConvertToTFModel(webhookResponse)
-	// Set the imported state
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
-}
@@ -3,6 +3,110 @@
 
 package provider
 
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestProjectChanged(t *testing.T) {
+	const project = "5e2b2a5f-1c3d-4e5f-8a9b-0c1d2e3f4a5b"
+	cases := []struct {
+		state, plan types.String
+		replace     bool
+	}{
+		{types.StringValue(project), types.StringValue(project), false},
+		{types.StringValue(project), types.StringValue("5E2B2A5F-1C3D-4E5F-8A9B-0C1D2E3F4A5B"), false},
+		{types.StringValue(project), types.StringValue("a8c2f3f1-0000-0000-0000-000000000000"), true},
+		{types.StringValue(project), types.StringUnknown(), true},
+		{types.StringValue(project), types.StringNull(), true},
+		{types.StringNull(), types.StringValue(project), true},
+	}
+
+	for _, c := range cases {
+		if projectChanged(c.state, c.plan) != c.replace {
+			t.Errorf("%v -> %v: expected replace %v", c.state, c.plan, c.replace)
+		}
+	}
+}
+
+func TestResolvePublisherNames(t *testing.T) {
+	requests := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.EscapedPath() {
+		case "/org/_apis/projects/My%20Project", "/org/_apis/projects/aaaa":
+			fmt.Fprint(w, `{"id": "aaaa", "name": "My Project"}`)
+		case "/org/aaaa/_apis/git/repositories/app-renamed", "/org/aaaa/_apis/git/repositories/bbbb":
+			fmt.Fprint(w, `{"id": "bbbb", "name": "app-renamed"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	unknownIds := func() *PublisherInputsTF {
+		return &PublisherInputsTF{ProjectId: GUIDValue{StringValue: types.StringUnknown()}, RepositoryId: GUIDValue{StringValue: types.StringUnknown()}}
+	}
+
+	config := &PublisherInputsTF{ProjectName: types.StringValue("My Project"), RepositoryName: types.StringValue("app-renamed")}
+	plan := unknownIds()
+	var diags diag.Diagnostics
+	resolvePublisherNames(client, config, nil, plan, &diags)
+	if diags.HasError() || plan.ProjectId.ValueString() != "aaaa" || plan.RepositoryId.ValueString() != "bbbb" {
+		t.Errorf("unexpected ids %v, %v: %v", plan.ProjectId, plan.RepositoryId, diags)
+	}
+
+	resolvePublisherNames(client, &PublisherInputsTF{ProjectName: types.StringValue("Missing")}, nil, unknownIds(), &diags)
+	if !diags.HasError() || diags[0].Summary() != "Unknown project" {
+		t.Errorf("expected an error for an unknown project, got %v", diags)
+	}
+
+	// A repository renamed outside of Terraform is reported with its new name
+	state := &PublisherInputsTF{
+		ProjectId: GUIDValue{StringValue: types.StringValue("aaaa")}, ProjectName: types.StringValue("My Project"),
+		RepositoryId: GUIDValue{StringValue: types.StringValue("bbbb")}, RepositoryName: types.StringValue("app"),
+	}
+	diags = nil
+	resolvePublisherNames(client, &PublisherInputsTF{ProjectName: types.StringValue("My Project"), RepositoryName: types.StringValue("app")}, state, unknownIds(), &diags)
+	if !diags.HasError() || diags[0].Summary() != "Renamed repository" || !strings.Contains(diags[0].Detail(), `"app-renamed"`) {
+		t.Errorf("expected the rename to be reported, got %v", diags)
+	}
+
+	// Configured ids are planned as they are
+	plan = unknownIds()
+	resolvePublisherNames(client, &PublisherInputsTF{ProjectId: GUIDValue{StringValue: types.StringValue("cccc")}}, nil, plan, &diags)
+	if plan.ProjectId.ValueString() != "cccc" || !plan.RepositoryId.IsNull() {
+		t.Errorf("unexpected ids %v, %v", plan.ProjectId, plan.RepositoryId)
+	}
+
+	// Names are only refreshed if the subscription was moved to another project or repository
+	data := *state
+	diags, requests = nil, 0
+	refreshPublisherNames(client, state, &data, &diags)
+	if diags.HasError() || requests != 0 || data.RepositoryName.ValueString() != "app" {
+		t.Errorf("expected names of unchanged ids to be kept without requests, got %v after %d requests: %v", data.RepositoryName, requests, diags)
+	}
+
+	moved := *state
+	moved.RepositoryId = GUIDValue{StringValue: types.StringValue("cccc")}
+	refreshPublisherNames(client, &moved, &data, &diags)
+	if diags.HasError() || requests != 1 || data.ProjectName.ValueString() != "My Project" || data.RepositoryName.ValueString() != "app-renamed" {
+		t.Errorf("unexpected names %v, %v after %d requests: %v", data.ProjectName, data.RepositoryName, requests, diags)
+	}
+}
+
 // func TestAccSubscriptionResource(t *testing.T) {
 // 	// Replace with actual values for testing
 // 	org := "your-organization-name"
@@ -91,7 +195,10 @@ package provider
 //     url                    = "%s"
 //     basic_auth_username    = "user"
 //     basic_auth_password    = "password"
-//     http_headers           = "Header1:Value1,Header2:Value2"
+//     headers = {
+//       Header1 = "Value1"
+//       Header2 = "Value2"
+//     }
 //     resource_details_to_send = "minimal"
 //     messages_to_send         = "none"
 //     detailed_messages_to_send = "none"
@@ -109,3 +216,331 @@ package provider
 // }
 // `, org, pat, consumerId, url, eventType, publisherId, repository, branch, pushedBy, projectId)
 // }
+
+func TestDeletionProtection(t *testing.T) {
+	ctx := context.Background()
+	s := subscriptionSchema(t)
+
+	prior := ConvertToTFModel(&WebhookSubscription{
+		ConsumerId:      "webHooks",
+		ConsumerInputs:  &ConsumerInputs{},
+		EventType:       stringToPointer("git.push"),
+		PublisherId:     stringToPointer("tfs"),
+		PublisherInputs: &PublisherInputs{},
+	})
+	prior.DeletionProtection = types.BoolValue(true)
+	state := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+	if diags := state.Set(ctx, prior); diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	var diags diag.Diagnostics
+	if !deletionProtected(ctx, state, &diags) || diags.HasError() {
+		t.Fatalf("expected the subscription to be protected: %v", diags)
+	}
+
+	plan := tfsdk.Plan{Schema: s, Raw: state.Raw}
+	if replaced := replacedAttributes(ctx, state, plan); len(replaced) != 0 {
+		t.Errorf("expected no replacement, got %v", replaced)
+	}
+
+	if diags := plan.SetAttribute(ctx, path.Root("event_type"), types.StringValue("git.pullrequest.created")); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if replaced := replacedAttributes(ctx, state, plan); len(replaced) != 1 || !replaced.Contains(path.Root("event_type")) {
+		t.Errorf("unexpected replaced attributes: %v", replaced)
+	}
+
+	// Replacements are blocked before the provider is configured as well
+	var resp resource.ModifyPlanResponse
+	(&SubscriptionResource{}).ModifyPlan(ctx, resource.ModifyPlanRequest{State: state, Plan: plan}, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Error("expected the replacement of a protected subscription to fail without a configured provider")
+	}
+}
+
+func TestPlanResourceVersion(t *testing.T) {
+	ctx := context.Background()
+	s := subscriptionSchema(t)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"value": [{"id": "git.pullrequest.created", "publisherId": "tfs", "supportedResourceVersions": ["1.0", "2.0-preview.1", "1.0-preview.1"]}]}`)
+	})
+	r := &SubscriptionResource{client: client}
+
+	subscription := func(eventType, resourceVersion string) *WebhookSubscriptionTF {
+		data := ConvertToTFModel(&WebhookSubscription{
+			ConsumerId:  "webHooks",
+			EventType:   stringToPointer(eventType),
+			PublisherId: stringToPointer("tfs"),
+		})
+		data.ResourceVersion = types.StringUnknown()
+		if resourceVersion != "" {
+			data.ResourceVersion = types.StringValue(resourceVersion)
+		}
+		return data
+	}
+
+	// plan runs planResourceVersion for the configured version, "" for none, and returns the planned one
+	plan := func(state *WebhookSubscriptionTF, eventType, configured string) (string, diag.Diagnostics) {
+		req := resource.ModifyPlanRequest{State: tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}}
+		resp := resource.ModifyPlanResponse{Plan: tfsdk.Plan{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}}
+
+		// Configuration cannot be set directly, it is written as a state first
+		config := subscription(eventType, configured)
+		if configured == "" {
+			config.ResourceVersion = types.StringNull()
+		}
+		configState := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+		diags := configState.Set(ctx, config)
+		req.Config = tfsdk.Config{Schema: s, Raw: configState.Raw}
+		diags.Append(resp.Plan.Set(ctx, subscription(eventType, configured))...)
+		if state != nil {
+			diags.Append(req.State.Set(ctx, state)...)
+		}
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+
+		var planned types.String
+		r.planResourceVersion(ctx, req, &resp)
+		resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("resource_version"), &planned)...)
+		return planned.ValueString(), resp.Diagnostics
+	}
+
+	if version, diags := plan(nil, "git.pullrequest.created", ""); diags.HasError() || version != "1.0" {
+		t.Errorf("expected the latest released version, got %q: %v", version, diags)
+	}
+	if version, diags := plan(nil, "git.push", ""); diags.HasError() || version != defaultResourceVersion {
+		t.Errorf("expected the default version for an event type without metadata, got %q: %v", version, diags)
+	}
+	if version, diags := plan(subscription("git.pullrequest.created", "1.0"), "git.pullrequest.created", ""); diags.HasError() || version != "1.0" {
+		t.Errorf("expected the version in state to be kept, got %q: %v", version, diags)
+	}
+	if version, diags := plan(subscription("git.push", "1.0"), "git.pullrequest.created", ""); diags.HasError() || version != "1.0" {
+		t.Errorf("expected the latest released version for a new event type, got %q: %v", version, diags)
+	}
+	if _, diags := plan(nil, "git.pullrequest.created", "1.0-preview.1"); diags.HasError() {
+		t.Errorf("expected a supported version to be accepted: %v", diags)
+	}
+	if _, diags := plan(nil, "git.pullrequest.created", "3.0"); !diags.HasError() {
+		t.Error("expected an error for an unsupported version")
+	}
+}
+
+func TestUpdateResendsSecrets(t *testing.T) {
+	ctx := context.Background()
+	s := subscriptionSchema(t)
+
+	var sent WebhookSubscription
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+			t.Error(err)
+		}
+		fmt.Fprint(w, `{"id": "subscription", "eventType": "servicebus.event", "publisherId": "tfs", "consumerId": "azureServiceBus",
+			"consumerInputs": {"url": "https://receiver.example.com/hook", "basicAuthPassword": "********",
+				"httpHeaders": "X-Api-Key:********", "connectionString": "********"},
+			"publisherInputs": {"projectId": "aaaa"}}`)
+	})
+
+	subscription := func(secretsVersion string) tfsdk.State {
+		data := ConvertToTFModel(&WebhookSubscription{
+			ID:          stringToPointer("subscription"),
+			ConsumerId:  "azureServiceBus",
+			EventType:   stringToPointer("servicebus.event"),
+			PublisherId: stringToPointer("tfs"),
+			ConsumerInputs: &ConsumerInputs{
+				URL:               stringToPointer("https://receiver.example.com/hook"),
+				BasicAuthPassword: stringToPointer("password"),
+			},
+			PublisherInputs: &PublisherInputs{ProjectId: stringToPointer("aaaa")},
+		})
+		data.ConsumerInputs.SensitiveHeaders = stringMapValue(map[string]string{"X-Api-Key": "key"})
+		data.SensitiveConsumerInputsMap = stringMapValue(map[string]string{"connectionString": "Endpoint=sb://"})
+		data.SecretsVersion = types.StringValue(secretsVersion)
+
+		state := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+		if diags := state.Set(ctx, data); diags.HasError() {
+			t.Fatal(diags)
+		}
+		return state
+	}
+
+	// update applies secrets_version to a subscription with version 1 and returns the request sent
+	update := func(secretsVersion string) (WebhookSubscription, resource.UpdateResponse) {
+		prior, planned := subscription("1"), subscription(secretsVersion)
+		req := resource.UpdateRequest{Plan: tfsdk.Plan{Schema: s, Raw: planned.Raw}, State: prior}
+		resp := resource.UpdateResponse{State: tfsdk.State{Schema: s, Raw: planned.Raw}}
+		newPrivateState(&resp.Private)
+		sent = WebhookSubscription{}
+		(&SubscriptionResource{client: client}).Update(ctx, req, &resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatal(resp.Diagnostics)
+		}
+		return sent, resp
+	}
+
+	rotated, resp := update("2")
+	ci := rotated.ConsumerInputs
+	if ci == nil || !reflect.DeepEqual(ci.BasicAuthPassword, stringToPointer("password")) || !reflect.DeepEqual(ci.HTTPHeaders, stringToPointer("X-Api-Key:key")) ||
+		ci.Additional["connectionString"] != "Endpoint=sb://" {
+		t.Errorf("expected all secrets to be sent, got %+v", ci)
+	}
+
+	// The configured secrets are kept in state, as Azure DevOps returns them masked
+	var data WebhookSubscriptionTF
+	resp.Diagnostics.Append(resp.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() || data.SecretsVersion.ValueString() != "2" || data.ConsumerInputs.BasicAuthPassword.ValueString() != "password" ||
+		stringMapValues(data.SensitiveConsumerInputsMap)["connectionString"] != "Endpoint=sb://" {
+		t.Errorf("unexpected state after update: %+v (%v)", data, resp.Diagnostics)
+	}
+	if password, diags := resp.Private.GetKey(ctx, passwordPrivateStateKey); diags.HasError() || password == nil {
+		t.Errorf("expected the password sent to be recorded in private state: %v", diags)
+	}
+
+	// secrets_version only forces an update, which sends all secrets whether it changed or not
+	if unchanged, _ := update("1"); !reflect.DeepEqual(unchanged.ConsumerInputs, rotated.ConsumerInputs) {
+		t.Errorf("expected the same secrets to be sent, got %+v and %+v", unchanged.ConsumerInputs, rotated.ConsumerInputs)
+	}
+}
+
+func TestModifyPlanUnknownInputs(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"value": []}`)
+	})
+
+	config := configWithUnknownInputs(t)
+	objectType := config.Schema.Type().TerraformType(ctx)
+	req := resource.ModifyPlanRequest{
+		Config: config,
+		Plan:   tfsdk.Plan{Schema: config.Schema, Raw: config.Raw},
+		State:  tfsdk.State{Schema: config.Schema, Raw: tftypes.NewValue(objectType, nil)},
+	}
+	resp := resource.ModifyPlanResponse{Plan: req.Plan}
+	(&SubscriptionResource{client: client}).ModifyPlan(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error planning unknown inputs: %v", resp.Diagnostics)
+	}
+
+	// The project of unknown publisher inputs may change, which replaces the subscription
+	prior := ConvertToTFModel(&WebhookSubscription{
+		ID:              stringToPointer("subscription"),
+		ConsumerId:      "webHooks",
+		ConsumerInputs:  &ConsumerInputs{URL: stringToPointer("https://receiver.example.com/hook")},
+		EventType:       stringToPointer("git.push"),
+		PublisherId:     stringToPointer("tfs"),
+		PublisherInputs: &PublisherInputs{ProjectId: stringToPointer("aaaa")},
+	})
+	if diags := req.State.Set(ctx, prior); diags.HasError() {
+		t.Fatal(diags)
+	}
+	resp = resource.ModifyPlanResponse{Plan: req.Plan}
+	(&SubscriptionResource{client: client}).ModifyPlan(ctx, req, &resp)
+	if resp.Diagnostics.HasError() || !resp.RequiresReplace.Contains(path.Root("publisher_inputs").AtName("project_id")) {
+		t.Errorf("expected a replacement for the unknown project, got %v: %v", resp.RequiresReplace, resp.Diagnostics)
+	}
+}
+
+func TestClientFor(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {})
+	r := &SubscriptionResource{client: client}
+
+	if c := r.clientFor(types.StringNull()); c != client {
+		t.Error("expected the provider client without an organization")
+	}
+	if c := r.clientFor(types.StringValue("org")); c != client {
+		t.Error("expected the provider client for the provider organization")
+	}
+
+	c := r.clientFor(types.StringValue("other"))
+	if c == client || c.Organization != "other" || client.Organization != "org" {
+		t.Fatalf("expected a copy for the other organization, got %q and %q", c.Organization, client.Organization)
+	}
+	if c.HTTPClient != client.HTTPClient || c.BaseURL != client.BaseURL || c.metadata != client.metadata {
+		t.Error("expected the copy to share the HTTP client, base URL and metadata cache")
+	}
+}
+
+func TestPlanOrganization(t *testing.T) {
+	ctx := context.Background()
+	s := subscriptionSchema(t)
+	r := &SubscriptionResource{client: newTestClient(t, func(w http.ResponseWriter, r *http.Request) {})}
+
+	subscription := func(organization types.String) tftypes.Value {
+		data := ConvertToTFModel(&WebhookSubscription{
+			ID:              stringToPointer("subscription"),
+			ConsumerId:      "webHooks",
+			ConsumerInputs:  &ConsumerInputs{},
+			EventType:       stringToPointer("git.push"),
+			PublisherId:     stringToPointer("tfs"),
+			PublisherInputs: &PublisherInputs{},
+		})
+		data.Organization = organization
+		state := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+		if diags := state.Set(ctx, data); diags.HasError() {
+			t.Fatal(diags)
+		}
+		return state.Raw
+	}
+
+	// plan runs planOrganization for the configured organization and returns the planned one
+	plan := func(prior *types.String, configured types.String) (string, path.Paths) {
+		req := resource.ModifyPlanRequest{
+			Config: tfsdk.Config{Schema: s, Raw: subscription(configured)},
+			State:  tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)},
+		}
+		if prior != nil {
+			req.State.Raw = subscription(*prior)
+		}
+		resp := resource.ModifyPlanResponse{Plan: tfsdk.Plan{Schema: s, Raw: req.Config.Raw}}
+
+		var planned types.String
+		r.planOrganization(ctx, req, &resp)
+		resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("organization"), &planned)...)
+		if resp.Diagnostics.HasError() {
+			t.Fatal(resp.Diagnostics)
+		}
+		return planned.ValueString(), resp.RequiresReplace
+	}
+
+	if organization, replaced := plan(nil, types.StringNull()); organization != "org" || len(replaced) != 0 {
+		t.Errorf("expected the provider organization without a replacement, got %q and %v", organization, replaced)
+	}
+	if organization, replaced := plan(nil, types.StringValue("other")); organization != "other" || len(replaced) != 0 {
+		t.Errorf("expected the configured organization without a replacement, got %q and %v", organization, replaced)
+	}
+
+	org, other, legacy := types.StringValue("org"), types.StringValue("other"), types.StringNull()
+	if _, replaced := plan(&org, types.StringNull()); len(replaced) != 0 {
+		t.Errorf("expected no replacement when the provider organization stays the same, got %v", replaced)
+	}
+	if _, replaced := plan(&org, other); !replaced.Contains(path.Root("organization")) {
+		t.Errorf("expected a replacement when the organization changes, got %v", replaced)
+	}
+	if _, replaced := plan(&other, types.StringNull()); !replaced.Contains(path.Root("organization")) {
+		t.Errorf("expected a replacement when falling back to another provider organization, got %v", replaced)
+	}
+	if _, replaced := plan(&legacy, other); len(replaced) != 0 {
+		t.Errorf("expected no replacement for a state without organization, got %v", replaced)
+	}
+}
+
+func subscriptionSchema(t *testing.T) schema.Schema {
+	var resp resource.SchemaResponse
+	(&SubscriptionResource{}).Schema(context.Background(), resource.SchemaRequest{}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+	return resp.Schema
+}
+
+// newPrivateState sets private to an empty private state. The framework creates it for requests and
+// responses, its type is internal and can only be instantiated through reflection.
+func newPrivateState(private interface{}) {
+	value := reflect.ValueOf(private).Elem()
+	value.Set(reflect.New(value.Type().Elem()))
+}
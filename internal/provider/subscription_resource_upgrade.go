@@ -0,0 +1,152 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// UpgradeState returns the upgraders migrating state of prior schema versions to the current one.
+// Terraform runs a single upgrader, so each one has to produce state of the current version. When
+// the schema version is bumped, freeze the current schema as the prior schema of a new upgrader and
+// adjust the existing upgraders to the new model.
+func (r *SubscriptionResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	schemaV0 := subscriptionSchemaV0()
+
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema:   &schemaV0,
+			StateUpgrader: upgradeSubscriptionStateV0,
+		},
+	}
+}
+
+// subscriptionSchemaV0 is the schema of the initial release, which had no version. Only the structure
+// matters for reading prior state, so descriptions and validators are left out.
+func subscriptionSchemaV0() schema.Schema {
+	return schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"consumer_action_id": schema.StringAttribute{Required: true},
+			"consumer_id":        schema.StringAttribute{Required: true},
+			"consumer_inputs": schema.SingleNestedAttribute{
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"url":                       schema.StringAttribute{Optional: true},
+					"basic_auth_username":       schema.StringAttribute{Optional: true},
+					"basic_auth_password":       schema.StringAttribute{Optional: true, Sensitive: true},
+					"http_headers":              schema.StringAttribute{Optional: true},
+					"resource_details_to_send":  schema.StringAttribute{Optional: true},
+					"messages_to_send":          schema.StringAttribute{Optional: true},
+					"detailed_messages_to_send": schema.StringAttribute{Optional: true},
+				},
+			},
+			"event_type":   schema.StringAttribute{Required: true},
+			"id":           schema.StringAttribute{Optional: true, Computed: true},
+			"publisher_id": schema.StringAttribute{Required: true},
+			"publisher_inputs": schema.SingleNestedAttribute{
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"repository":          schema.StringAttribute{Optional: true},
+					"branch":              schema.StringAttribute{Optional: true},
+					"pushed_by":           schema.StringAttribute{Optional: true},
+					"project_id":          schema.StringAttribute{Optional: true},
+					"tfs_subscription_id": schema.StringAttribute{Optional: true, Computed: true},
+				},
+			},
+			"resource_version": schema.StringAttribute{Optional: true},
+			"scope":            schema.Int64Attribute{Optional: true},
+		},
+	}
+}
+
+type consumerInputsModelV0 struct {
+	URL                    types.String `tfsdk:"url"`
+	BasicAuthUsername      types.String `tfsdk:"basic_auth_username"`
+	BasicAuthPassword      types.String `tfsdk:"basic_auth_password"`
+	HTTPHeaders            types.String `tfsdk:"http_headers"`
+	ResourceDetailsToSend  types.String `tfsdk:"resource_details_to_send"`
+	MessagesToSend         types.String `tfsdk:"messages_to_send"`
+	DetailedMessagesToSend types.String `tfsdk:"detailed_messages_to_send"`
+}
+
+type publisherInputsModelV0 struct {
+	RepositoryId      types.String `tfsdk:"repository"`
+	Branch            types.String `tfsdk:"branch"`
+	PushedBy          types.String `tfsdk:"pushed_by"`
+	ProjectId         types.String `tfsdk:"project_id"`
+	TfsSubscriptionId types.String `tfsdk:"tfs_subscription_id"`
+}
+
+type subscriptionModelV0 struct {
+	ConsumerActionId types.String            `tfsdk:"consumer_action_id"`
+	ConsumerId       types.String            `tfsdk:"consumer_id"`
+	ConsumerInputs   *consumerInputsModelV0  `tfsdk:"consumer_inputs"`
+	EventType        types.String            `tfsdk:"event_type"`
+	ID               types.String            `tfsdk:"id"`
+	PublisherId      types.String            `tfsdk:"publisher_id"`
+	PublisherInputs  *publisherInputsModelV0 `tfsdk:"publisher_inputs"`
+	ResourceVersion  types.String            `tfsdk:"resource_version"`
+	Scope            types.Int64             `tfsdk:"scope"`
+}
+
+// upgradeSubscriptionStateV0 parses the newline-separated http_headers string into the headers map
+// and fills in the defaults of the attributes added since. Without an organization the subscription
+// keeps belonging to the provider organization.
+func upgradeSubscriptionStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var prior subscriptionModelV0
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	upgraded := WebhookSubscriptionTF{
+		AdoptExisting:              types.BoolValue(false),
+		ConsumerActionId:           prior.ConsumerActionId,
+		ConsumerId:                 prior.ConsumerId,
+		ConsumerInputsMap:          types.MapNull(types.StringType),
+		DeletionProtection:         types.BoolValue(false),
+		Enabled:                    types.BoolValue(true),
+		EventType:                  prior.EventType,
+		ID:                         prior.ID,
+		Organization:               types.StringNull(),
+		PublisherId:                prior.PublisherId,
+		PublisherInputsMap:         types.MapNull(types.StringType),
+		ResourceVersion:            prior.ResourceVersion,
+		RestoreFromProbation:       types.BoolValue(false),
+		Scope:                      prior.Scope,
+		SensitiveConsumerInputsMap: types.MapNull(types.StringType),
+		VerifyFailureMode:          types.StringValue(verifyFailureModeError),
+		VerifyOnApply:              types.BoolValue(false),
+	}
+
+	if ci := prior.ConsumerInputs; ci != nil {
+		upgraded.ConsumerInputs = &ConsumerInputsTF{
+			URL:                    URLValue{StringValue: ci.URL},
+			AcceptUntrustedCerts:   types.BoolValue(false),
+			BasicAuthUsername:      ci.BasicAuthUsername,
+			BasicAuthPassword:      ci.BasicAuthPassword,
+			HTTPHeaders:            headersValue(ci.HTTPHeaders.ValueStringPointer()),
+			SensitiveHeaders:       types.MapNull(types.StringType),
+			ResourceDetailsToSend:  ci.ResourceDetailsToSend,
+			MessagesToSend:         ci.MessagesToSend,
+			DetailedMessagesToSend: ci.DetailedMessagesToSend,
+		}
+	}
+
+	if pi := prior.PublisherInputs; pi != nil {
+		upgraded.PublisherInputs = &PublisherInputsTF{
+			RepositoryId:      GUIDValue{StringValue: pi.RepositoryId},
+			Branch:            BranchValue{StringValue: pi.Branch},
+			PushedBy:          pi.PushedBy,
+			ProjectId:         GUIDValue{StringValue: pi.ProjectId},
+			TfsSubscriptionId: GUIDValue{StringValue: pi.TfsSubscriptionId},
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, upgraded)...)
+}
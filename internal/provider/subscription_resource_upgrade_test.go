@@ -0,0 +1,143 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// upgradeSubscriptionState runs the upgrader of version against state JSON written with that version.
+func upgradeSubscriptionState(t *testing.T, version int64, stateJSON string) (WebhookSubscriptionTF, resource.UpgradeStateResponse) {
+	ctx := context.Background()
+	upgrader, ok := (&SubscriptionResource{}).UpgradeState(ctx)[version]
+	if !ok {
+		t.Fatalf("no upgrader for version %d", version)
+	}
+
+	priorType := upgrader.PriorSchema.Type().TerraformType(ctx)
+	raw, err := (&tfprotov6.RawState{JSON: []byte(stateJSON)}).UnmarshalWithOpts(priorType, tfprotov6.UnmarshalOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	current := subscriptionSchema(t)
+	req := resource.UpgradeStateRequest{State: &tfsdk.State{Schema: *upgrader.PriorSchema, Raw: raw}}
+	resp := resource.UpgradeStateResponse{
+		State: tfsdk.State{Schema: current, Raw: tftypes.NewValue(current.Type().TerraformType(ctx), nil)},
+	}
+	upgrader.StateUpgrader(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+
+	var upgraded WebhookSubscriptionTF
+	if diags := resp.State.Get(ctx, &upgraded); diags.HasError() {
+		t.Fatal(diags)
+	}
+	return upgraded, resp
+}
+
+func TestSubscriptionSchema(t *testing.T) {
+	ctx := context.Background()
+	s := subscriptionSchema(t)
+	if diags := s.ValidateImplementation(ctx); diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	// Every attribute must be mapped by the model, otherwise reading or writing state fails
+	state := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+	data := ConvertToTFModel(&WebhookSubscription{ConsumerInputs: &ConsumerInputs{}, PublisherInputs: &PublisherInputs{}})
+	if diags := state.Set(ctx, data); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if diags := state.Get(ctx, &WebhookSubscriptionTF{}); diags.HasError() {
+		t.Fatal(diags)
+	}
+}
+
+func TestUpgradeSubscriptionStateV0(t *testing.T) {
+	upgraded, _ := upgradeSubscriptionState(t, 0, `{
+		"consumer_action_id": "httpRequest",
+		"consumer_id": "webHooks",
+		"consumer_inputs": {
+			"url": "https://receiver.example.com/hook",
+			"basic_auth_username": "user",
+			"basic_auth_password": "secret",
+			"http_headers": "X-Team: platform\nX-Source:ado",
+			"resource_details_to_send": "all",
+			"messages_to_send": null,
+			"detailed_messages_to_send": null
+		},
+		"event_type": "git.push",
+		"id": "5e2b2a5f-1c3d-4e5f-8a9b-0c1d2e3f4a5b",
+		"publisher_id": "tfs",
+		"publisher_inputs": {
+			"repository": "repository",
+			"branch": "main",
+			"pushed_by": null,
+			"project_id": "project",
+			"tfs_subscription_id": "tfs-id"
+		},
+		"resource_version": "1.0",
+		"scope": 1
+	}`)
+
+	headers := stringMapValues(upgraded.ConsumerInputs.HTTPHeaders)
+	if len(headers) != 2 || headers["X-Team"] != "platform" || headers["X-Source"] != "ado" {
+		t.Errorf("unexpected headers: %v", headers)
+	}
+	if !upgraded.ConsumerInputs.SensitiveHeaders.IsNull() {
+		t.Errorf("expected no sensitive headers, got %v", upgraded.ConsumerInputs.SensitiveHeaders)
+	}
+	if upgraded.ConsumerInputs.BasicAuthPassword.ValueString() != "secret" || upgraded.PublisherInputs.TfsSubscriptionId.ValueString() != "tfs-id" {
+		t.Errorf("inputs not preserved: %+v, %+v", upgraded.ConsumerInputs, upgraded.PublisherInputs)
+	}
+	if upgraded.ID.ValueString() != "5e2b2a5f-1c3d-4e5f-8a9b-0c1d2e3f4a5b" || upgraded.ResourceVersion.ValueString() != "1.0" {
+		t.Errorf("unexpected id or resource version: %v, %v", upgraded.ID, upgraded.ResourceVersion)
+	}
+
+	// Attributes added since the initial release get their defaults
+	if !upgraded.Organization.IsNull() || !upgraded.ConsumerInputsMap.IsNull() || !upgraded.PublisherInputsMap.IsNull() || !upgraded.SensitiveConsumerInputsMap.IsNull() {
+		t.Errorf("expected no organization and input maps: %v, %v, %v, %v", upgraded.Organization, upgraded.ConsumerInputsMap, upgraded.PublisherInputsMap, upgraded.SensitiveConsumerInputsMap)
+	}
+	if !upgraded.Enabled.ValueBool() || upgraded.RestoreFromProbation.IsNull() || upgraded.RestoreFromProbation.ValueBool() {
+		t.Errorf("expected enabled to default to true and restore_from_probation to false, got %v and %v", upgraded.Enabled, upgraded.RestoreFromProbation)
+	}
+	if upgraded.DeletionProtection.IsNull() || upgraded.DeletionProtection.ValueBool() || upgraded.AdoptExisting.IsNull() || upgraded.AdoptExisting.ValueBool() {
+		t.Errorf("expected deletion_protection and adopt_existing to default to false, got %v and %v", upgraded.DeletionProtection, upgraded.AdoptExisting)
+	}
+	if upgraded.VerifyOnApply.IsNull() || upgraded.VerifyOnApply.ValueBool() || upgraded.VerifyFailureMode.ValueString() != verifyFailureModeError {
+		t.Errorf("unexpected verification defaults: %v, %v", upgraded.VerifyOnApply, upgraded.VerifyFailureMode)
+	}
+	if upgraded.ConsumerInputs.AcceptUntrustedCerts.IsNull() || upgraded.ConsumerInputs.AcceptUntrustedCerts.ValueBool() {
+		t.Errorf("expected accept_untrusted_certs to default to false, got %v", upgraded.ConsumerInputs.AcceptUntrustedCerts)
+	}
+}
+
+func TestUpgradeSubscriptionStateV0WithoutInputs(t *testing.T) {
+	upgraded, _ := upgradeSubscriptionState(t, 0, `{
+		"consumer_action_id": "httpRequest",
+		"consumer_id": "webHooks",
+		"consumer_inputs": {"url": "https://receiver.example.com/hook"},
+		"event_type": "git.push",
+		"id": "5e2b2a5f-1c3d-4e5f-8a9b-0c1d2e3f4a5b",
+		"publisher_id": "tfs",
+		"publisher_inputs": null,
+		"resource_version": null,
+		"scope": 1
+	}`)
+
+	if upgraded.PublisherInputs != nil || !upgraded.ResourceVersion.IsNull() {
+		t.Errorf("unexpected publisher inputs or resource version: %+v, %v", upgraded.PublisherInputs, upgraded.ResourceVersion)
+	}
+	if !upgraded.ConsumerInputs.HTTPHeaders.IsNull() {
+		t.Errorf("expected missing headers to be null: %v", upgraded.ConsumerInputs.HTTPHeaders)
+	}
+}
@@ -3,36 +3,151 @@
 
 package provider
 
-import "github.com/hashicorp/terraform-plugin-framework/types"
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
 
 type ConsumerInputsTF struct {
-	URL                    types.String `tfsdk:"url"`
+	URL                    URLValue     `tfsdk:"url"`
+	AcceptUntrustedCerts   types.Bool   `tfsdk:"accept_untrusted_certs"`
 	BasicAuthUsername      types.String `tfsdk:"basic_auth_username"`
 	BasicAuthPassword      types.String `tfsdk:"basic_auth_password"`
-	HTTPHeaders            types.String `tfsdk:"http_headers"`
+	HTTPHeaders            types.Map    `tfsdk:"headers"`
+	SensitiveHeaders       types.Map    `tfsdk:"sensitive_headers"`
 	ResourceDetailsToSend  types.String `tfsdk:"resource_details_to_send"`
 	MessagesToSend         types.String `tfsdk:"messages_to_send"`
 	DetailedMessagesToSend types.String `tfsdk:"detailed_messages_to_send"`
 }
 
 type PublisherInputsTF struct {
-	RepositoryId      types.String `tfsdk:"repository"`
-	Branch            types.String `tfsdk:"branch"`
+	RepositoryId      GUIDValue    `tfsdk:"repository"`
+	RepositoryName    types.String `tfsdk:"repository_name"`
+	Branch            BranchValue  `tfsdk:"branch"`
 	PushedBy          types.String `tfsdk:"pushed_by"`
-	ProjectId         types.String `tfsdk:"project_id"`
-	TfsSubscriptionId types.String `tfsdk:"tfs_subscription_id"`
+	ProjectId         GUIDValue    `tfsdk:"project_id"`
+	ProjectName       types.String `tfsdk:"project_name"`
+	TfsSubscriptionId GUIDValue    `tfsdk:"tfs_subscription_id"`
 }
 
 type WebhookSubscriptionTF struct {
-	ConsumerActionId types.String       `tfsdk:"consumer_action_id"`
-	ConsumerId       types.String       `tfsdk:"consumer_id"`
-	ConsumerInputs   *ConsumerInputsTF  `tfsdk:"consumer_inputs"`
-	EventType        types.String       `tfsdk:"event_type"`
-	ID               types.String       `tfsdk:"id"`
-	PublisherId      types.String       `tfsdk:"publisher_id"`
-	PublisherInputs  *PublisherInputsTF `tfsdk:"publisher_inputs"`
-	ResourceVersion  types.String       `tfsdk:"resource_version"`
-	Scope            types.Int64        `tfsdk:"scope"`
+	ActionDescription          types.String       `tfsdk:"action_description"`
+	AdoptExisting              types.Bool         `tfsdk:"adopt_existing"`
+	ConsumerActionId           types.String       `tfsdk:"consumer_action_id"`
+	ConsumerId                 types.String       `tfsdk:"consumer_id"`
+	ConsumerInputs             *ConsumerInputsTF  `tfsdk:"consumer_inputs"`
+	ConsumerInputsMap          types.Map          `tfsdk:"consumer_inputs_map"`
+	CreatedBy                  types.String       `tfsdk:"created_by"`
+	CreatedDate                types.String       `tfsdk:"created_date"`
+	DeletionProtection         types.Bool         `tfsdk:"deletion_protection"`
+	Enabled                    types.Bool         `tfsdk:"enabled"`
+	EventDescription           types.String       `tfsdk:"event_description"`
+	EventType                  types.String       `tfsdk:"event_type"`
+	ID                         types.String       `tfsdk:"id"`
+	LastProbationRetryDate     types.String       `tfsdk:"last_probation_retry_date"`
+	ModifiedBy                 types.String       `tfsdk:"modified_by"`
+	ModifiedDate               types.String       `tfsdk:"modified_date"`
+	Organization               types.String       `tfsdk:"organization"`
+	ProbationRetries           types.Int64        `tfsdk:"probation_retries"`
+	PublisherId                types.String       `tfsdk:"publisher_id"`
+	PublisherInputs            *PublisherInputsTF `tfsdk:"publisher_inputs"`
+	PublisherInputsMap         types.Map          `tfsdk:"publisher_inputs_map"`
+	ResourceVersion            types.String       `tfsdk:"resource_version"`
+	RestoreFromProbation       types.Bool         `tfsdk:"restore_from_probation"`
+	Scope                      types.Int64        `tfsdk:"scope"`
+	SecretsVersion             types.String       `tfsdk:"secrets_version"`
+	SensitiveConsumerInputsMap types.Map          `tfsdk:"sensitive_consumer_inputs_map"`
+	Status                     types.String       `tfsdk:"status"`
+	URL                        types.String       `tfsdk:"url"`
+	VerifyFailureMode          types.String       `tfsdk:"verify_failure_mode"`
+	VerifyOnApply              types.Bool         `tfsdk:"verify_on_apply"`
+}
+
+// consumerInputsByID returns the consumer inputs keyed by their Azure DevOps input id.
+func (ws *WebhookSubscriptionTF) consumerInputsByID() map[string]types.String {
+	inputs := map[string]types.String{}
+	if ci := ws.ConsumerInputs; ci != nil {
+		inputs["url"] = ci.URL.StringValue
+		inputs["acceptUntrustedCerts"] = boolInputString(ci.AcceptUntrustedCerts)
+		inputs["basicAuthUsername"] = ci.BasicAuthUsername
+		inputs["basicAuthPassword"] = ci.BasicAuthPassword
+		inputs["httpHeaders"] = ci.httpHeaders()
+		inputs["resourceDetailsToSend"] = ci.ResourceDetailsToSend
+		inputs["messagesToSend"] = ci.MessagesToSend
+		inputs["detailedMessagesToSend"] = ci.DetailedMessagesToSend
+	}
+	for _, m := range []types.Map{ws.ConsumerInputsMap, ws.SensitiveConsumerInputsMap} {
+		for id, value := range m.Elements() {
+			if s, ok := value.(types.String); ok {
+				inputs[id] = s
+			}
+		}
+	}
+	return inputs
+}
+
+// httpHeaders returns the plain and sensitive headers serialized into a single input value.
+func (ci *ConsumerInputsTF) httpHeaders() types.String {
+	if ci.HTTPHeaders.IsUnknown() || ci.SensitiveHeaders.IsUnknown() {
+		return types.StringUnknown()
+	}
+
+	headers := mergeInputMaps(stringMapValues(ci.HTTPHeaders), stringMapValues(ci.SensitiveHeaders))
+	if len(headers) == 0 {
+		return types.StringNull()
+	}
+	return types.StringValue(formatHeaders(headers))
+}
+
+// publisherInputsByID returns the publisher inputs keyed by their Azure DevOps input id.
+func (ws *WebhookSubscriptionTF) publisherInputsByID() map[string]types.String {
+	inputs := map[string]types.String{}
+	if pi := ws.PublisherInputs; pi != nil {
+		inputs["repository"] = pi.RepositoryId.StringValue
+		inputs["branch"] = pi.Branch.StringValue
+		inputs["pushedBy"] = pi.PushedBy
+		inputs["projectId"] = pi.ProjectId.StringValue
+		inputs["tfsSubscriptionId"] = pi.TfsSubscriptionId.StringValue
+	}
+	for id, value := range ws.PublisherInputsMap.Elements() {
+		if s, ok := value.(types.String); ok {
+			inputs[id] = s
+		}
+	}
+	return inputs
+}
+
+// Statuses of a subscription. Only enabled and disabledByUser can be set, the others are set by
+// Azure DevOps when deliveries keep failing.
+const (
+	subscriptionStatusEnabled                    = "enabled"
+	subscriptionStatusOnProbation                = "onProbation"
+	subscriptionStatusDisabledByUser             = "disabledByUser"
+	subscriptionStatusDisabledBySystem           = "disabledBySystem"
+	subscriptionStatusDisabledByInactiveIdentity = "disabledByInactiveIdentity"
+)
+
+// isDegradedStatus reports whether Azure DevOps suspended the subscription on its own.
+func isDegradedStatus(status string) bool {
+	switch status {
+	case subscriptionStatusOnProbation, subscriptionStatusDisabledBySystem, subscriptionStatusDisabledByInactiveIdentity:
+		return true
+	}
+	return false
+}
+
+// statusFromEnabled returns the status to send for the enabled attribute.
+func statusFromEnabled(enabled types.Bool) *string {
+	if enabled.IsNull() || enabled.IsUnknown() {
+		return nil
+	}
+	if enabled.ValueBool() {
+		return stringToPointer(subscriptionStatusEnabled)
+	}
+	return stringToPointer(subscriptionStatusDisabledByUser)
 }
 
 func stringToPointer(s string) *string {
@@ -46,10 +161,14 @@ func int64Pointer(i int64) *int64 {
 	return &i
 }
 
+// defaultResourceVersion is the resource version used when the supported versions of the event type
+// cannot be retrieved.
+const defaultResourceVersion = "1.0"
+
 func DefaultWebhookSubscriptionTF() *WebhookSubscriptionTF {
 	return &WebhookSubscriptionTF{
 		ConsumerId:      types.StringValue("webHooks"),
-		ResourceVersion: types.StringValue("1.0"),
+		ResourceVersion: types.StringValue(defaultResourceVersion),
 		Scope:           types.Int64Value(1),
 	}
 }
@@ -59,7 +178,7 @@ func (ws *WebhookSubscriptionTF) SetDefaults() {
 		ws.ConsumerId = types.StringValue("webHooks")
 	}
 	if ws.ResourceVersion.IsNull() || ws.ResourceVersion.IsUnknown() {
-		ws.ResourceVersion = types.StringValue("1.0")
+		ws.ResourceVersion = types.StringValue(defaultResourceVersion)
 	}
 	if ws.Scope.IsNull() || ws.Scope.IsUnknown() {
 		ws.Scope = types.Int64Value(1)
@@ -79,7 +198,7 @@ func (ws *WebhookSubscription) SetDefaults() {
 
 	// Set default value for ResourceVersion if not set
 	if ws.ResourceVersion == nil {
-		ws.ResourceVersion = stringToPointer("1.0")
+		ws.ResourceVersion = stringToPointer(defaultResourceVersion)
 	}
 
 	// Set default value for Scope if not set
@@ -90,12 +209,39 @@ func (ws *WebhookSubscription) SetDefaults() {
 
 type ConsumerInputs struct {
 	URL                    *string `json:"url,omitempty"`
+	AcceptUntrustedCerts   *string `json:"acceptUntrustedCerts,omitempty"`
 	BasicAuthUsername      *string `json:"basicAuthUsername,omitempty"`
 	BasicAuthPassword      *string `json:"basicAuthPassword,omitempty"`
 	HTTPHeaders            *string `json:"httpHeaders,omitempty"`
 	ResourceDetailsToSend  *string `json:"resourceDetailsToSend,omitempty"`
 	MessagesToSend         *string `json:"messagesToSend,omitempty"`
 	DetailedMessagesToSend *string `json:"detailedMessagesToSend,omitempty"`
+
+	// Additional holds the inputs of consumers which are not modelled by the fields above
+	Additional map[string]string `json:"-"`
+}
+
+// consumerInputsFields has the fields of ConsumerInputs without its JSON methods.
+type consumerInputsFields ConsumerInputs
+
+func (ci ConsumerInputs) MarshalJSON() ([]byte, error) {
+	return marshalInputs(consumerInputsFields(ci), ci.Additional)
+}
+
+func (ci *ConsumerInputs) UnmarshalJSON(data []byte) error {
+	var fields consumerInputsFields
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	additional, err := unmarshalAdditionalInputs(data, fields)
+	if err != nil {
+		return err
+	}
+
+	*ci = ConsumerInputs(fields)
+	ci.Additional = additional
+	return nil
 }
 
 type PublisherInputs struct {
@@ -104,6 +250,32 @@ type PublisherInputs struct {
 	PushedBy          *string `json:"pushedBy,omitempty"`
 	ProjectId         *string `json:"projectId,omitempty"`
 	TfsSubscriptionId *string `json:"tfsSubscriptionId,omitempty"`
+
+	// Additional holds the event filters which are not modelled by the fields above
+	Additional map[string]string `json:"-"`
+}
+
+// publisherInputsFields has the fields of PublisherInputs without its JSON methods.
+type publisherInputsFields PublisherInputs
+
+func (pi PublisherInputs) MarshalJSON() ([]byte, error) {
+	return marshalInputs(publisherInputsFields(pi), pi.Additional)
+}
+
+func (pi *PublisherInputs) UnmarshalJSON(data []byte) error {
+	var fields publisherInputsFields
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	additional, err := unmarshalAdditionalInputs(data, fields)
+	if err != nil {
+		return err
+	}
+
+	*pi = PublisherInputs(fields)
+	pi.Additional = additional
+	return nil
 }
 
 type WebhookSubscription struct {
@@ -116,72 +288,306 @@ type WebhookSubscription struct {
 	PublisherInputs  *PublisherInputs `json:"publisherInputs,omitempty"`
 	ResourceVersion  *string          `json:"resourceVersion,omitempty"`
 	Scope            *int64           `json:"scope,omitempty"`
+	Status           *string          `json:"status,omitempty"`
+
+	// Read-only fields set by Azure DevOps, they are never sent
+	ActionDescription      *string      `json:"actionDescription,omitempty"`
+	CreatedBy              *IdentityRef `json:"createdBy,omitempty"`
+	CreatedDate            *string      `json:"createdDate,omitempty"`
+	EventDescription       *string      `json:"eventDescription,omitempty"`
+	LastProbationRetryDate *string      `json:"lastProbationRetryDate,omitempty"`
+	ModifiedBy             *IdentityRef `json:"modifiedBy,omitempty"`
+	ModifiedDate           *string      `json:"modifiedDate,omitempty"`
+	ProbationRetries       *int64       `json:"probationRetries,omitempty"`
+	URL                    *string      `json:"url,omitempty"`
+}
+
+// IdentityRef is the user or service principal which created or modified a subscription.
+type IdentityRef struct {
+	ID          string `json:"id,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+	UniqueName  string `json:"uniqueName,omitempty"`
+}
+
+// identityName returns the unique name of an identity, which is its email address or account name
+// for users, falling back to the display name.
+func identityName(identity *IdentityRef) types.String {
+	if identity == nil {
+		return types.StringNull()
+	}
+	if identity.UniqueName != "" {
+		return types.StringValue(identity.UniqueName)
+	}
+	return types.StringValue(identity.DisplayName)
 }
 
 func DefaultWebhookSubscription() *WebhookSubscription {
 	return &WebhookSubscription{
 		ConsumerId:      "webHooks",
 		PublisherId:     stringToPointer("tfs"),
-		ResourceVersion: stringToPointer("1.0"),
+		ResourceVersion: stringToPointer(defaultResourceVersion),
 		Scope:           int64Pointer(1),
 	}
 }
 
 func ConvertToJSONModel(tf *WebhookSubscriptionTF) *WebhookSubscription {
-	return &WebhookSubscription{
-		ConsumerActionId: getOptionalString(tf.ConsumerActionId),
-		ConsumerId:       tf.ConsumerId.ValueString(),
-		ConsumerInputs: &ConsumerInputs{
-			URL:                    getOptionalString(tf.ConsumerInputs.URL),
+	consumerInputs := &ConsumerInputs{}
+	if tf.ConsumerInputs != nil {
+		consumerInputs = &ConsumerInputs{
+			URL:                    getOptionalString(tf.ConsumerInputs.URL.StringValue),
+			AcceptUntrustedCerts:   getOptionalString(boolInputString(tf.ConsumerInputs.AcceptUntrustedCerts)),
 			BasicAuthUsername:      getOptionalString(tf.ConsumerInputs.BasicAuthUsername),
 			BasicAuthPassword:      getOptionalString(tf.ConsumerInputs.BasicAuthPassword),
-			HTTPHeaders:            getOptionalString(tf.ConsumerInputs.HTTPHeaders),
+			HTTPHeaders:            getOptionalString(tf.ConsumerInputs.httpHeaders()),
 			ResourceDetailsToSend:  getOptionalString(tf.ConsumerInputs.ResourceDetailsToSend),
 			MessagesToSend:         getOptionalString(tf.ConsumerInputs.MessagesToSend),
 			DetailedMessagesToSend: getOptionalString(tf.ConsumerInputs.DetailedMessagesToSend),
-		},
-		EventType:   getOptionalString(tf.EventType),
-		ID:          getOptionalString(tf.ID),
-		PublisherId: getOptionalString(tf.PublisherId),
-		PublisherInputs: &PublisherInputs{
-			RepositoryId:      getOptionalString(tf.PublisherInputs.RepositoryId),
-			Branch:            getOptionalString(tf.PublisherInputs.Branch),
+		}
+	}
+	consumerInputs.Additional = mergeInputMaps(stringMapValues(tf.ConsumerInputsMap), stringMapValues(tf.SensitiveConsumerInputsMap))
+
+	publisherInputs := &PublisherInputs{}
+	if tf.PublisherInputs != nil {
+		publisherInputs = &PublisherInputs{
+			RepositoryId:      getOptionalString(tf.PublisherInputs.RepositoryId.StringValue),
+			Branch:            getOptionalString(tf.PublisherInputs.Branch.StringValue),
 			PushedBy:          getOptionalString(tf.PublisherInputs.PushedBy),
-			ProjectId:         getOptionalString(tf.PublisherInputs.ProjectId),
-			TfsSubscriptionId: getOptionalString(tf.PublisherInputs.TfsSubscriptionId),
-		},
-		ResourceVersion: getOptionalString(tf.ResourceVersion),
-		Scope:           getOptionalInt64(tf.Scope),
+			ProjectId:         getOptionalString(tf.PublisherInputs.ProjectId.StringValue),
+			TfsSubscriptionId: getOptionalString(tf.PublisherInputs.TfsSubscriptionId.StringValue),
+		}
+	}
+	publisherInputs.Additional = stringMapValues(tf.PublisherInputsMap)
+
+	return &WebhookSubscription{
+		ConsumerActionId: getOptionalString(tf.ConsumerActionId),
+		ConsumerId:       tf.ConsumerId.ValueString(),
+		ConsumerInputs:   consumerInputs,
+		EventType:        getOptionalString(tf.EventType),
+		ID:               getOptionalString(tf.ID),
+		PublisherId:      getOptionalString(tf.PublisherId),
+		PublisherInputs:  publisherInputs,
+		ResourceVersion:  getOptionalString(tf.ResourceVersion),
+		Scope:            getOptionalInt64(tf.Scope),
+		Status:           statusFromEnabled(tf.Enabled),
 	}
 }
 
 // Convert from JSON structs to Terraform SDK structs.
 func ConvertToTFModel(json *WebhookSubscription) *WebhookSubscriptionTF {
+	// Input objects are omitted by the API for subscriptions without inputs
+	var consumerInputsMap, publisherInputsMap map[string]string
+	if json.ConsumerInputs != nil {
+		consumerInputsMap = json.ConsumerInputs.Additional
+	}
+	if json.PublisherInputs != nil {
+		publisherInputsMap = json.PublisherInputs.Additional
+	}
+
 	return &WebhookSubscriptionTF{
-		ConsumerActionId: types.StringPointerValue(json.ConsumerActionId),
-		ConsumerId:       types.StringValue(json.ConsumerId),
-		ConsumerInputs: &ConsumerInputsTF{
-			URL:                    types.StringPointerValue(json.ConsumerInputs.URL),
-			BasicAuthUsername:      types.StringPointerValue(json.ConsumerInputs.BasicAuthUsername),
-			BasicAuthPassword:      types.StringPointerValue(json.ConsumerInputs.BasicAuthPassword),
-			HTTPHeaders:            types.StringPointerValue(json.ConsumerInputs.HTTPHeaders),
-			ResourceDetailsToSend:  types.StringPointerValue(json.ConsumerInputs.ResourceDetailsToSend),
-			MessagesToSend:         types.StringPointerValue(json.ConsumerInputs.MessagesToSend),
-			DetailedMessagesToSend: types.StringPointerValue(json.ConsumerInputs.DetailedMessagesToSend),
-		},
-		EventType:   types.StringPointerValue(json.EventType),
-		ID:          types.StringPointerValue(json.ID),
-		PublisherId: types.StringPointerValue(json.PublisherId),
-		PublisherInputs: &PublisherInputsTF{
-			RepositoryId:      types.StringPointerValue(json.PublisherInputs.RepositoryId),
-			Branch:            types.StringPointerValue(json.PublisherInputs.Branch),
-			PushedBy:          types.StringPointerValue(json.PublisherInputs.PushedBy),
-			ProjectId:         types.StringPointerValue(json.PublisherInputs.ProjectId),
-			TfsSubscriptionId: types.StringPointerValue(json.PublisherInputs.TfsSubscriptionId),
-		},
-		ResourceVersion: types.StringPointerValue(json.ResourceVersion),
-		Scope:           types.Int64PointerValue(json.Scope),
+		ConsumerActionId:           types.StringPointerValue(json.ConsumerActionId),
+		ConsumerId:                 types.StringValue(json.ConsumerId),
+		ConsumerInputs:             consumerInputsTF(json.ConsumerInputs),
+		ConsumerInputsMap:          stringMapValue(consumerInputsMap),
+		SensitiveConsumerInputsMap: types.MapNull(types.StringType),
+		EventType:                  types.StringPointerValue(json.EventType),
+		ID:                         types.StringPointerValue(json.ID),
+		PublisherId:                types.StringPointerValue(json.PublisherId),
+		PublisherInputs:            publisherInputsTF(json.PublisherInputs),
+		PublisherInputsMap:         stringMapValue(publisherInputsMap),
+		ResourceVersion:            types.StringPointerValue(json.ResourceVersion),
+		Scope:                      types.Int64PointerValue(json.Scope),
+		Status:                     types.StringPointerValue(json.Status),
+		// Subscriptions suspended by Azure DevOps are still enabled from the user's point of view
+		Enabled: types.BoolValue(json.Status == nil || *json.Status != subscriptionStatusDisabledByUser),
+
+		ActionDescription:      types.StringPointerValue(json.ActionDescription),
+		CreatedBy:              identityName(json.CreatedBy),
+		CreatedDate:            types.StringPointerValue(json.CreatedDate),
+		EventDescription:       types.StringPointerValue(json.EventDescription),
+		LastProbationRetryDate: types.StringPointerValue(json.LastProbationRetryDate),
+		ModifiedBy:             identityName(json.ModifiedBy),
+		ModifiedDate:           types.StringPointerValue(json.ModifiedDate),
+		ProbationRetries:       types.Int64PointerValue(json.ProbationRetries),
+		URL:                    types.StringPointerValue(json.URL),
+
+		// Attributes unknown to Azure DevOps start with their schema defaults, which is what imports
+		// keep. Otherwise keepLocalAttributes replaces them with the prior values.
+		AdoptExisting:        types.BoolValue(false),
+		DeletionProtection:   types.BoolValue(false),
+		RestoreFromProbation: types.BoolValue(false),
+		VerifyFailureMode:    types.StringValue(verifyFailureModeError),
+		VerifyOnApply:        types.BoolValue(false),
+	}
+}
+
+// consumerInputsTF converts the typed consumer inputs. Consumers without typed inputs, such as most
+// consumers other than webHooks, have no consumer_inputs.
+func consumerInputsTF(ci *ConsumerInputs) *ConsumerInputsTF {
+	if ci == nil || (ci.URL == nil && ci.AcceptUntrustedCerts == nil && ci.BasicAuthUsername == nil && ci.BasicAuthPassword == nil && ci.HTTPHeaders == nil &&
+		ci.ResourceDetailsToSend == nil && ci.MessagesToSend == nil && ci.DetailedMessagesToSend == nil) {
+		return nil
+	}
+
+	return &ConsumerInputsTF{
+		URL:                    normalizedStringPointerValue[urlNormalization](ci.URL),
+		AcceptUntrustedCerts:   boolInputValue(ci.AcceptUntrustedCerts),
+		BasicAuthUsername:      types.StringPointerValue(ci.BasicAuthUsername),
+		BasicAuthPassword:      types.StringPointerValue(ci.BasicAuthPassword),
+		HTTPHeaders:            headersValue(ci.HTTPHeaders),
+		SensitiveHeaders:       types.MapNull(types.StringType),
+		ResourceDetailsToSend:  types.StringPointerValue(ci.ResourceDetailsToSend),
+		MessagesToSend:         types.StringPointerValue(ci.MessagesToSend),
+		DetailedMessagesToSend: types.StringPointerValue(ci.DetailedMessagesToSend),
+	}
+}
+
+// publisherInputsTF converts the typed publisher inputs. Publishers without typed inputs have no
+// publisher_inputs.
+func publisherInputsTF(pi *PublisherInputs) *PublisherInputsTF {
+	if pi == nil || (pi.RepositoryId == nil && pi.Branch == nil && pi.PushedBy == nil && pi.ProjectId == nil && pi.TfsSubscriptionId == nil) {
+		return nil
+	}
+
+	return &PublisherInputsTF{
+		RepositoryId:      normalizedStringPointerValue[guidNormalization](pi.RepositoryId),
+		Branch:            normalizedStringPointerValue[branchNormalization](pi.Branch),
+		PushedBy:          types.StringPointerValue(pi.PushedBy),
+		ProjectId:         normalizedStringPointerValue[guidNormalization](pi.ProjectId),
+		TfsSubscriptionId: normalizedStringPointerValue[guidNormalization](pi.TfsSubscriptionId),
+	}
+}
+
+// keepLocalAttributes copies the attributes which are not part of the API response from prior into data.
+func keepLocalAttributes(prior, data *WebhookSubscriptionTF) {
+	data.AdoptExisting = prior.AdoptExisting
+	data.DeletionProtection = prior.DeletionProtection
+	data.RestoreFromProbation = prior.RestoreFromProbation
+	data.SecretsVersion = prior.SecretsVersion
+	data.VerifyFailureMode = prior.VerifyFailureMode
+	data.VerifyOnApply = prior.VerifyOnApply
+
+	// Input objects without typed inputs are converted to null, keep them if they are configured
+	if prior.ConsumerInputs != nil && data.ConsumerInputs == nil {
+		data.ConsumerInputs = &ConsumerInputsTF{
+			AcceptUntrustedCerts: types.BoolValue(false),
+			HTTPHeaders:          types.MapNull(types.StringType),
+			SensitiveHeaders:     types.MapNull(types.StringType),
+		}
+	}
+	if prior.PublisherInputs != nil && data.PublisherInputs == nil {
+		data.PublisherInputs = &PublisherInputsTF{}
+	}
+
+	// Azure DevOps adds the tfsSubscriptionId input to subscriptions of the tfs publisher, which alone
+	// must not turn up as publisher_inputs that are not configured
+	if prior.PublisherInputs == nil && data.PublisherInputs != nil && onlyTfsSubscriptionId(data.PublisherInputs) {
+		data.PublisherInputs = nil
+	}
+
+	// Names are resolved by the provider, Azure DevOps only knows the ids
+	if prior.PublisherInputs != nil && data.PublisherInputs != nil {
+		data.PublisherInputs.ProjectName = prior.PublisherInputs.ProjectName
+		data.PublisherInputs.RepositoryName = prior.PublisherInputs.RepositoryName
+	}
+
+	data.ConsumerInputsMap = configuredInputs(data.ConsumerInputsMap, prior.ConsumerInputsMap)
+	data.PublisherInputsMap = configuredInputs(data.PublisherInputsMap, prior.PublisherInputsMap)
+}
+
+func onlyTfsSubscriptionId(pi *PublisherInputsTF) bool {
+	return pi.RepositoryId.IsNull() && pi.Branch.IsNull() && pi.PushedBy.IsNull() && pi.ProjectId.IsNull()
+}
+
+// surfaceDegradedStatus reports a subscription suspended by Azure DevOps as disabled if it should be
+// restored, so the difference to the configuration is planned as an update re-enabling it.
+func surfaceDegradedStatus(data *WebhookSubscriptionTF) {
+	if data.RestoreFromProbation.ValueBool() && isDegradedStatus(data.Status.ValueString()) {
+		data.Enabled = types.BoolValue(false)
+	}
+}
+
+// probationDetail describes the failed deliveries which led Azure DevOps to suspend a subscription.
+func probationDetail(ws *WebhookSubscription) string {
+	detail := "Azure DevOps suspended the subscription"
+	if ws.Status != nil {
+		detail += fmt.Sprintf(" (%s)", *ws.Status)
+	}
+	if ws.ProbationRetries != nil {
+		detail += fmt.Sprintf(" after %d probation retries", *ws.ProbationRetries)
+	}
+	if ws.LastProbationRetryDate != nil {
+		detail += fmt.Sprintf(", the last delivery failed on %s", *ws.LastProbationRetryDate)
+	}
+	return detail + ". It has been re-enabled, check that the receiver is reachable again."
+}
+
+// restoreSecrets copies the secrets of prior into data. Azure DevOps masks secrets in its responses,
+// so the values last sent are kept in state to compare against the configuration. Sensitive inputs
+// which are no longer returned by Azure DevOps are dropped so they show up as drift.
+func restoreSecrets(prior, data *WebhookSubscriptionTF) {
+	if prior.ConsumerInputs != nil && !prior.ConsumerInputs.BasicAuthPassword.IsNull() && data.ConsumerInputs != nil {
+		data.ConsumerInputs.BasicAuthPassword = prior.ConsumerInputs.BasicAuthPassword
+	}
+
+	// Sensitive headers come back as part of the plain headers, move them back by name. Masked values
+	// are replaced by the prior ones, other values are taken as returned so changes show up as drift.
+	if prior.ConsumerInputs != nil && data.ConsumerInputs != nil {
+		headers := stringMapValues(data.ConsumerInputs.HTTPHeaders)
+		sensitiveHeaders := map[string]string{}
+		for name, value := range stringMapValues(prior.ConsumerInputs.SensitiveHeaders) {
+			if returnedName, ok := findHeader(headers, name); ok {
+				sensitiveHeaders[name] = headers[returnedName]
+				if isMasked(headers[returnedName]) {
+					sensitiveHeaders[name] = value
+				}
+				delete(headers, returnedName)
+			}
+		}
+
+		data.ConsumerInputs.HTTPHeaders = stringMapValue(headers)
+		data.ConsumerInputs.SensitiveHeaders = stringMapValue(sensitiveHeaders)
+	}
+
+	returned := stringMapValues(data.ConsumerInputsMap)
+	sensitive := map[string]string{}
+	for id, value := range stringMapValues(prior.SensitiveConsumerInputsMap) {
+		if _, ok := returned[id]; ok {
+			sensitive[id] = value
+			delete(returned, id)
+		}
+	}
+
+	data.ConsumerInputsMap = stringMapValue(returned)
+	data.SensitiveConsumerInputsMap = stringMapValue(sensitive)
+}
+
+// headersValue parses the serialized headers of the API into a map.
+func headersValue(headers *string) types.Map {
+	if headers == nil {
+		return types.MapNull(types.StringType)
+	}
+	return stringMapValue(parseHeaders(*headers))
+}
+
+// boolInputString returns a boolean input the way Azure DevOps expects it, as "true" or "false".
+func boolInputString(b types.Bool) types.String {
+	if b.IsUnknown() {
+		return types.StringUnknown()
+	}
+	if b.IsNull() {
+		return types.StringNull()
+	}
+	return types.StringValue(strconv.FormatBool(b.ValueBool()))
+}
+
+// boolInputValue parses a boolean input returned by Azure DevOps. Missing or unparsable values are
+// false, which is how Azure DevOps treats them.
+func boolInputValue(s *string) types.Bool {
+	if s == nil {
+		return types.BoolValue(false)
 	}
+	b, err := strconv.ParseBool(*s)
+	return types.BoolValue(err == nil && b)
 }
 
 // Helper function to handle optional strings in Terraform SDK.
@@ -0,0 +1,297 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestConsumerInputsAdditionalRoundTrip(t *testing.T) {
+	inputs := ConsumerInputs{
+		URL:        stringToPointer("https://example.com"),
+		Additional: map[string]string{"queueName": "hooks", "url": "ignored"},
+	}
+
+	data, err := json.Marshal(inputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded ConsumerInputs
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if *decoded.URL != "https://example.com" || !reflect.DeepEqual(decoded.Additional, map[string]string{"queueName": "hooks"}) {
+		t.Errorf("unexpected round trip result: %s", data)
+	}
+
+	if err := json.Unmarshal([]byte(`{"url":"https://example.com","retries":3,"enabled":true}`), &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(decoded.Additional, map[string]string{"retries": "3", "enabled": "true"}) {
+		t.Errorf("unexpected additional inputs: %v", decoded.Additional)
+	}
+}
+
+func TestAcceptUntrustedCerts(t *testing.T) {
+	plan := DefaultWebhookSubscriptionTF()
+	plan.ConsumerInputs = &ConsumerInputsTF{AcceptUntrustedCerts: types.BoolValue(true)}
+
+	request := ConvertToJSONModel(plan)
+	if request.ConsumerInputs.AcceptUntrustedCerts == nil || *request.ConsumerInputs.AcceptUntrustedCerts != "true" {
+		t.Fatalf("expected acceptUntrustedCerts to be sent as \"true\", got %v", request.ConsumerInputs.AcceptUntrustedCerts)
+	}
+
+	cases := map[string]bool{"true": true, "True": true, "false": false, "yes": false}
+	for value, expected := range cases {
+		data := ConvertToTFModel(&WebhookSubscription{ConsumerInputs: &ConsumerInputs{AcceptUntrustedCerts: stringToPointer(value)}})
+		if data.ConsumerInputs == nil || data.ConsumerInputs.AcceptUntrustedCerts.ValueBool() != expected {
+			t.Errorf("%q: expected %t, got %+v", value, expected, data.ConsumerInputs)
+		}
+	}
+
+	// Subscriptions created without the input accept only trusted certificates
+	data := ConvertToTFModel(&WebhookSubscription{ConsumerInputs: &ConsumerInputs{URL: stringToPointer("https://example.com")}})
+	if data.ConsumerInputs.AcceptUntrustedCerts.IsNull() || data.ConsumerInputs.AcceptUntrustedCerts.ValueBool() {
+		t.Errorf("expected false, got %v", data.ConsumerInputs.AcceptUntrustedCerts)
+	}
+}
+
+func TestRestoreSecrets(t *testing.T) {
+	prior := WebhookSubscriptionTF{
+		ConsumerInputs:             &ConsumerInputsTF{BasicAuthPassword: types.StringValue("secret")},
+		SensitiveConsumerInputsMap: stringMapValue(map[string]string{"connectionString": "Endpoint=sb://", "sasToken": "token"}),
+	}
+	data := ConvertToTFModel(&WebhookSubscription{
+		ConsumerInputs: &ConsumerInputs{
+			BasicAuthPassword: stringToPointer("********"),
+			Additional:        map[string]string{"connectionString": "********", "queueName": "hooks"},
+		},
+		PublisherInputs: &PublisherInputs{},
+	})
+
+	restoreSecrets(&prior, data)
+
+	if data.ConsumerInputs.BasicAuthPassword.ValueString() != "secret" {
+		t.Errorf("password not restored: %v", data.ConsumerInputs.BasicAuthPassword)
+	}
+
+	if !reflect.DeepEqual(stringMapValues(data.ConsumerInputsMap), map[string]string{"queueName": "hooks"}) {
+		t.Errorf("unexpected consumer inputs: %v", data.ConsumerInputsMap)
+	}
+
+	// sasToken is no longer returned and must show up as drift
+	if !reflect.DeepEqual(stringMapValues(data.SensitiveConsumerInputsMap), map[string]string{"connectionString": "Endpoint=sb://"}) {
+		t.Errorf("unexpected sensitive consumer inputs: %v", data.SensitiveConsumerInputsMap)
+	}
+}
+
+func TestPublisherInputsMapRoundTrip(t *testing.T) {
+	tf := &WebhookSubscriptionTF{
+		PublisherInputs:    &PublisherInputsTF{ProjectId: GUIDValue{StringValue: types.StringValue("project")}},
+		PublisherInputsMap: stringMapValue(map[string]string{"definitionName": "ci", "buildStatus": "failed"}),
+	}
+
+	data, err := json.Marshal(ConvertToJSONModel(tf))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var subscription WebhookSubscription
+	if err := json.Unmarshal(data, &subscription); err != nil {
+		t.Fatal(err)
+	}
+
+	roundTripped := ConvertToTFModel(&subscription)
+	if roundTripped.PublisherInputs.ProjectId.ValueString() != "project" || !roundTripped.PublisherInputsMap.Equal(tf.PublisherInputsMap) {
+		t.Errorf("unexpected round trip result: %s", data)
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	headers := parseHeaders("X-Trigger: devops\r\nAuthorization:Bearer a:b\n\n:orphan\nX-Empty")
+	expected := map[string]string{"X-Trigger": "devops", "Authorization": "Bearer a:b", "X-Empty": ""}
+	if !reflect.DeepEqual(headers, expected) {
+		t.Errorf("unexpected headers: %v", headers)
+	}
+
+	if formatted := formatHeaders(headers); formatted != "Authorization:Bearer a:b\nX-Empty:\nX-Trigger:devops" {
+		t.Errorf("unexpected formatted headers: %q", formatted)
+	}
+}
+
+func TestRestoreSensitiveHeaders(t *testing.T) {
+	prior := WebhookSubscriptionTF{
+		ConsumerInputs: &ConsumerInputsTF{SensitiveHeaders: stringMapValue(map[string]string{"X-Api-Key": "old"})},
+	}
+	data := ConvertToTFModel(&WebhookSubscription{
+		ConsumerInputs:  &ConsumerInputs{HTTPHeaders: stringToPointer("x-api-key:new\nX-Trigger:devops")},
+		PublisherInputs: &PublisherInputs{},
+	})
+
+	restoreSecrets(&prior, data)
+
+	if !reflect.DeepEqual(stringMapValues(data.ConsumerInputs.HTTPHeaders), map[string]string{"X-Trigger": "devops"}) {
+		t.Errorf("unexpected headers: %v", data.ConsumerInputs.HTTPHeaders)
+	}
+
+	if !reflect.DeepEqual(stringMapValues(data.ConsumerInputs.SensitiveHeaders), map[string]string{"X-Api-Key": "new"}) {
+		t.Errorf("unexpected sensitive headers: %v", data.ConsumerInputs.SensitiveHeaders)
+	}
+
+	// Masked values keep the prior value instead of showing up as drift
+	data = ConvertToTFModel(&WebhookSubscription{
+		ConsumerInputs:  &ConsumerInputs{HTTPHeaders: stringToPointer("X-Api-Key:********")},
+		PublisherInputs: &PublisherInputs{},
+	})
+	restoreSecrets(&prior, data)
+	if !reflect.DeepEqual(stringMapValues(data.ConsumerInputs.SensitiveHeaders), map[string]string{"X-Api-Key": "old"}) {
+		t.Errorf("expected the masked header to keep its prior value, got %v", data.ConsumerInputs.SensitiveHeaders)
+	}
+}
+
+func TestSubscriptionStatus(t *testing.T) {
+	cases := map[string]bool{
+		subscriptionStatusEnabled:          true,
+		subscriptionStatusOnProbation:      true,
+		subscriptionStatusDisabledBySystem: true,
+		subscriptionStatusDisabledByUser:   false,
+	}
+
+	for status, enabled := range cases {
+		data := ConvertToTFModel(&WebhookSubscription{
+			ConsumerInputs:  &ConsumerInputs{},
+			PublisherInputs: &PublisherInputs{},
+			Status:          stringToPointer(status),
+		})
+
+		if data.Enabled.ValueBool() != enabled || data.Status.ValueString() != status {
+			t.Errorf("status %q: unexpected enabled %v", status, data.Enabled)
+		}
+	}
+
+	if status := statusFromEnabled(types.BoolValue(false)); *status != subscriptionStatusDisabledByUser {
+		t.Errorf("unexpected status for disabled subscription: %s", *status)
+	}
+}
+
+func TestSurfaceDegradedStatus(t *testing.T) {
+	for _, restore := range []bool{true, false} {
+		data := WebhookSubscriptionTF{
+			Enabled:              types.BoolValue(true),
+			RestoreFromProbation: types.BoolValue(restore),
+			Status:               types.StringValue(subscriptionStatusOnProbation),
+		}
+		surfaceDegradedStatus(&data)
+
+		if data.Enabled.ValueBool() == restore {
+			t.Errorf("restore %v: unexpected enabled %v", restore, data.Enabled)
+		}
+	}
+
+	detail := probationDetail(&WebhookSubscription{
+		Status:                 stringToPointer(subscriptionStatusDisabledBySystem),
+		ProbationRetries:       int64Pointer(3),
+		LastProbationRetryDate: stringToPointer("2024-05-01T10:00:00Z"),
+	})
+	for _, want := range []string{subscriptionStatusDisabledBySystem, "3 probation retries", "2024-05-01T10:00:00Z"} {
+		if !strings.Contains(detail, want) {
+			t.Errorf("detail %q does not mention %q", detail, want)
+		}
+	}
+}
+
+func TestSubscriptionMetadata(t *testing.T) {
+	var response WebhookSubscription
+	err := json.Unmarshal([]byte(`{
+		"id": "5e2b2a5f-0000-0000-0000-000000000000",
+		"consumerInputs": {},
+		"publisherInputs": {},
+		"createdBy": {"displayName": "Jane Doe", "uniqueName": "jane@example.com"},
+		"createdDate": "2024-05-01T10:00:00Z",
+		"modifiedBy": {"displayName": "Build Service"},
+		"probationRetries": 2,
+		"url": "https://dev.azure.com/org/_apis/hooks/subscriptions/5e2b2a5f-0000-0000-0000-000000000000"
+	}`), &response)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := ConvertToTFModel(&response)
+	if data.CreatedBy.ValueString() != "jane@example.com" || data.ModifiedBy.ValueString() != "Build Service" {
+		t.Errorf("unexpected identities: %v, %v", data.CreatedBy, data.ModifiedBy)
+	}
+	if data.CreatedDate.ValueString() != "2024-05-01T10:00:00Z" || data.ProbationRetries.ValueInt64() != 2 || data.URL.IsNull() {
+		t.Errorf("unexpected metadata: %+v", data)
+	}
+	if !data.ModifiedDate.IsNull() || !data.LastProbationRetryDate.IsNull() {
+		t.Errorf("expected missing dates to be null: %v, %v", data.ModifiedDate, data.LastProbationRetryDate)
+	}
+}
+
+func TestConvertToTFModelOptionalInputs(t *testing.T) {
+	cases := map[string]string{
+		"absent":  `{"consumerId": "webHooks"}`,
+		"null":    `{"consumerId": "webHooks", "consumerInputs": null, "publisherInputs": null}`,
+		"untyped": `{"consumerId": "azureServiceBus", "consumerInputs": {"queueName": "events"}, "publisherInputs": {"buildStatus": "failed"}}`,
+	}
+
+	for name, response := range cases {
+		var ws WebhookSubscription
+		if err := json.Unmarshal([]byte(response), &ws); err != nil {
+			t.Fatal(err)
+		}
+
+		data := ConvertToTFModel(&ws)
+		if data.ConsumerInputs != nil || data.PublisherInputs != nil {
+			t.Errorf("%s: expected no typed inputs, got %+v, %+v", name, data.ConsumerInputs, data.PublisherInputs)
+		}
+		if name == "untyped" && (stringMapValues(data.ConsumerInputsMap)["queueName"] != "events" || stringMapValues(data.PublisherInputsMap)["buildStatus"] != "failed") {
+			t.Errorf("%s: unexpected maps %v, %v", name, data.ConsumerInputsMap, data.PublisherInputsMap)
+		}
+	}
+
+	// Partial inputs only set what is returned
+	data := ConvertToTFModel(&WebhookSubscription{PublisherInputs: &PublisherInputs{Branch: stringToPointer("main")}})
+	if data.PublisherInputs == nil || data.PublisherInputs.Branch.ValueString() != "main" || !data.PublisherInputs.ProjectId.IsNull() {
+		t.Errorf("unexpected publisher inputs: %+v", data.PublisherInputs)
+	}
+}
+
+func TestKeepLocalInputObjects(t *testing.T) {
+	// The computed tfsSubscriptionId alone does not create publisher_inputs
+	data := ConvertToTFModel(&WebhookSubscription{PublisherInputs: &PublisherInputs{TfsSubscriptionId: stringToPointer("tfs")}})
+	keepLocalAttributes(&WebhookSubscriptionTF{}, data)
+	if data.PublisherInputs != nil {
+		t.Errorf("expected no publisher inputs, got %+v", data.PublisherInputs)
+	}
+
+	// Configured but empty input objects are kept
+	data = ConvertToTFModel(&WebhookSubscription{})
+	keepLocalAttributes(&WebhookSubscriptionTF{ConsumerInputs: &ConsumerInputsTF{}, PublisherInputs: &PublisherInputsTF{}}, data)
+	if data.ConsumerInputs == nil || data.PublisherInputs == nil || !data.ConsumerInputs.HTTPHeaders.IsNull() {
+		t.Errorf("expected empty input objects, got %+v, %+v", data.ConsumerInputs, data.PublisherInputs)
+	}
+}
+
+func TestKeepConfiguredPublisherInputs(t *testing.T) {
+	prior := &WebhookSubscriptionTF{PublisherInputsMap: stringMapValue(map[string]string{"buildStatus": "Failed"})}
+
+	data := ConvertToTFModel(&WebhookSubscription{ConsumerInputs: &ConsumerInputs{}, PublisherInputs: &PublisherInputs{Additional: map[string]string{
+		"buildStatus":    "Failed",
+		"definitionName": "",
+	}}})
+	keepLocalAttributes(prior, data)
+
+	if !reflect.DeepEqual(stringMapValues(data.PublisherInputsMap), map[string]string{"buildStatus": "Failed"}) {
+		t.Errorf("expected only configured inputs, got %v", data.PublisherInputsMap)
+	}
+}
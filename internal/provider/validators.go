@@ -0,0 +1,398 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ validator.String         = knownValueValidator{}
+	_ resource.ConfigValidator = catalogConfigValidator{}
+	_ resource.ConfigValidator = webhookURLConfigValidator{}
+	_ resource.ConfigValidator = untrustedCertsConfigValidator{}
+	_ resource.ConfigValidator = inputMapsConfigValidator{}
+	_ resource.ConfigValidator = headersConfigValidator{}
+	_ resource.ConfigValidator = secretHeadersConfigValidator{}
+	_ resource.ConfigValidator = publisherNamesConfigValidator{}
+)
+
+// knownValueValidator checks a string attribute against a list of known values. Unknown values are
+// rejected only if the list is exhaustive, otherwise they produce a warning and are passed through to
+// Azure DevOps. Values close to a known one are reported with a suggestion.
+type knownValueValidator struct {
+	kind       string
+	values     []string
+	exhaustive bool
+}
+
+func (v knownValueValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("value must be a known %s: %s", v.kind, strings.Join(v.values, ", "))
+}
+
+func (v knownValueValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v knownValueValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	if contains(v.values, value) {
+		return
+	}
+
+	// Values missing from a list which is not exhaustive may be new ones, so typos are only warned about
+	if suggestion := suggest(value, v.values); suggestion != "" {
+		summary := "Unknown " + v.kind
+		detail := fmt.Sprintf("%q is not a known %s, did you mean %q?", value, v.kind, suggestion)
+		if v.exhaustive {
+			resp.Diagnostics.AddAttributeError(req.Path, summary, detail)
+		} else {
+			resp.Diagnostics.AddAttributeWarning(req.Path, summary, detail+" It is passed to Azure DevOps unvalidated.")
+		}
+		return
+	}
+
+	if v.exhaustive {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid "+v.kind,
+			fmt.Sprintf("%q is not a valid %s, expected one of: %s.", value, v.kind, strings.Join(v.values, ", ")),
+		)
+		return
+	}
+
+	resp.Diagnostics.AddAttributeWarning(
+		req.Path,
+		"Unknown "+v.kind,
+		fmt.Sprintf("%q is not a %s known to the provider and is passed to Azure DevOps unvalidated.", value, v.kind),
+	)
+}
+
+func publisherIdValidator() validator.String {
+	return knownValueValidator{kind: "publisher", values: knownPublisherIds()}
+}
+
+func eventTypeValidator() validator.String {
+	return knownValueValidator{kind: "event type", values: allKnownEventTypes()}
+}
+
+func consumerIdValidator() validator.String {
+	return knownValueValidator{kind: "consumer", values: knownConsumerIds()}
+}
+
+func consumerActionIdValidator() validator.String {
+	return knownValueValidator{kind: "consumer action", values: allKnownConsumerActions()}
+}
+
+func resourceDetailsValidator() validator.String {
+	return knownValueValidator{kind: "resource detail level", values: resourceDetailsValues, exhaustive: true}
+}
+
+func messagesValidator() validator.String {
+	return knownValueValidator{kind: "message format", values: messageValues, exhaustive: true}
+}
+
+func verifyFailureModeValidator() validator.String {
+	return knownValueValidator{kind: "failure mode", values: verifyFailureModes, exhaustive: true}
+}
+
+// catalogConfigValidator checks that the event type belongs to the publisher and the action to the
+// consumer, as far as both are part of the catalog.
+type catalogConfigValidator struct{}
+
+func (v catalogConfigValidator) Description(_ context.Context) string {
+	return "event_type must be published by publisher_id and consumer_action_id must be offered by consumer_id"
+}
+
+func (v catalogConfigValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v catalogConfigValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var publisherId, eventType, consumerId, consumerActionId types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("publisher_id"), &publisherId)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("event_type"), &eventType)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("consumer_id"), &consumerId)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("consumer_action_id"), &consumerActionId)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	validateCatalogPair(path.Root("event_type"), "event type", "publisher", publisherId.ValueString(), eventType.ValueString(), knownEventTypes, resp)
+	validateCatalogPair(path.Root("consumer_action_id"), "action", "consumer", consumerId.ValueString(), consumerActionId.ValueString(), knownConsumerActions, resp)
+}
+
+// validateCatalogPair reports an error when value is not offered by owner although both are known.
+// Unknown (empty) values are skipped, they are validated once known.
+func validateCatalogPair(attribute path.Path, kind, ownerKind, owner, value string, catalog map[string][]string, resp *resource.ValidateConfigResponse) {
+	values, ok := catalog[owner]
+	if !ok || value == "" || contains(values, value) {
+		return
+	}
+
+	// Values of other owners are reported as mismatch, everything else is up to the attribute validator
+	if !contains(flattenValues(catalog), value) {
+		return
+	}
+
+	detail := fmt.Sprintf("The %s %q does not offer the %s %q. Known values for %q: %s.", ownerKind, owner, kind, value, owner, strings.Join(values, ", "))
+	resp.Diagnostics.AddAttributeError(attribute, "Invalid "+kind+" for "+ownerKind, detail)
+}
+
+// webhookURLConfigValidator requires a target URL for web hook HTTP requests.
+type webhookURLConfigValidator struct{}
+
+func (v webhookURLConfigValidator) Description(_ context.Context) string {
+	return "consumer_inputs.url is required when consumer_id is webHooks and consumer_action_id is httpRequest"
+}
+
+func (v webhookURLConfigValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v webhookURLConfigValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var consumerId, consumerActionId types.String
+	var consumerInputs types.Object
+	var url URLValue
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("consumer_id"), &consumerId)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("consumer_action_id"), &consumerActionId)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("consumer_inputs"), &consumerInputs)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("consumer_inputs").AtName("url"), &url)...)
+	if resp.Diagnostics.HasError() || consumerInputs.IsUnknown() {
+		return
+	}
+
+	if consumerId.ValueString() != "webHooks" || consumerActionId.ValueString() != "httpRequest" {
+		return
+	}
+
+	if url.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("consumer_inputs").AtName("url"),
+			"Missing webhook URL",
+			"The webHooks consumer requires consumer_inputs.url to be set for the httpRequest action.",
+		)
+	}
+}
+
+// untrustedCertsConfigValidator rejects accepting untrusted certificates for receivers which are not
+// called over https, where there is no certificate to accept.
+type untrustedCertsConfigValidator struct{}
+
+func (v untrustedCertsConfigValidator) Description(_ context.Context) string {
+	return "consumer_inputs.accept_untrusted_certs requires an https consumer_inputs.url"
+}
+
+func (v untrustedCertsConfigValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v untrustedCertsConfigValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var acceptUntrustedCerts types.Bool
+	var url URLValue
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("consumer_inputs").AtName("accept_untrusted_certs"), &acceptUntrustedCerts)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("consumer_inputs").AtName("url"), &url)...)
+	if resp.Diagnostics.HasError() || !acceptUntrustedCerts.ValueBool() || url.IsNull() || url.IsUnknown() {
+		return
+	}
+
+	if !strings.HasPrefix(strings.ToLower(url.ValueString()), "https://") {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("consumer_inputs").AtName("accept_untrusted_certs"),
+			"Untrusted certificates without https",
+			fmt.Sprintf("accept_untrusted_certs only applies to https receivers, but the URL %q does not use https.", url.ValueString()),
+		)
+	}
+}
+
+// inputMapsConfigValidator rejects free-form inputs which are modelled by a typed attribute of
+// consumer_inputs or publisher_inputs, and consumer inputs set in both the plain and the sensitive map.
+type inputMapsConfigValidator struct{}
+
+func (v inputMapsConfigValidator) Description(_ context.Context) string {
+	return "consumer_inputs_map, sensitive_consumer_inputs_map and publisher_inputs_map must not contain inputs of consumer_inputs, publisher_inputs or of each other"
+}
+
+func (v inputMapsConfigValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v inputMapsConfigValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var consumerInputs, sensitiveConsumerInputs, publisherInputs types.Map
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("consumer_inputs_map"), &consumerInputs)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("sensitive_consumer_inputs_map"), &sensitiveConsumerInputs)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("publisher_inputs_map"), &publisherInputs)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	typed := typedInputAttributes(ConsumerInputsTF{}, ConsumerInputs{})
+	validateInputMapKeys(path.Root("consumer_inputs_map"), consumerInputs, "consumer_inputs", typed, resp)
+	validateInputMapKeys(path.Root("sensitive_consumer_inputs_map"), sensitiveConsumerInputs, "consumer_inputs", typed, resp)
+	validateInputMapKeys(path.Root("publisher_inputs_map"), publisherInputs, "publisher_inputs", typedInputAttributes(PublisherInputsTF{}, PublisherInputs{}), resp)
+
+	sensitive := sensitiveConsumerInputs.Elements()
+	for id := range consumerInputs.Elements() {
+		if _, ok := sensitive[id]; ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("consumer_inputs_map").AtMapKey(id),
+				"Conflicting consumer input",
+				fmt.Sprintf("The input %q is set in both consumer_inputs_map and sensitive_consumer_inputs_map.", id),
+			)
+		}
+	}
+}
+
+// validateInputMapKeys reports map keys which have a typed attribute in the given block.
+func validateInputMapKeys(attribute path.Path, m types.Map, block string, typed map[string]string, resp *resource.ValidateConfigResponse) {
+	for id := range m.Elements() {
+		if name, ok := typed[id]; ok {
+			resp.Diagnostics.AddAttributeError(
+				attribute.AtMapKey(id),
+				"Conflicting input",
+				fmt.Sprintf("The input %q is modelled by %s.%s, set it there instead.", id, block, name),
+			)
+		}
+	}
+}
+
+// headersConfigValidator checks that headers can be serialized into "Name:Value" lines and that no
+// header is set both as plain and as sensitive header.
+type headersConfigValidator struct{}
+
+func (v headersConfigValidator) Description(_ context.Context) string {
+	return "header names must not contain colons or line breaks, values must not contain line breaks, and headers and sensitive_headers must not overlap"
+}
+
+func (v headersConfigValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v headersConfigValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var headers, sensitiveHeaders types.Map
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("consumer_inputs").AtName("headers"), &headers)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("consumer_inputs").AtName("sensitive_headers"), &sensitiveHeaders)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plain := headers.Elements()
+	sensitive := sensitiveHeaders.Elements()
+	for attribute, headers := range map[string]map[string]attr.Value{"headers": plain, "sensitive_headers": sensitive} {
+		for name, value := range headers {
+			headerPath := path.Root("consumer_inputs").AtName(attribute).AtMapKey(name)
+			if strings.TrimSpace(name) == "" || strings.ContainsAny(name, ":\r\n") {
+				resp.Diagnostics.AddAttributeError(headerPath, "Invalid header name",
+					fmt.Sprintf("The header name %q must not be empty or contain colons or line breaks.", name))
+			}
+			if s, ok := value.(types.String); ok && strings.ContainsAny(s.ValueString(), "\r\n") {
+				resp.Diagnostics.AddAttributeError(headerPath, "Invalid header value",
+					fmt.Sprintf("The value of header %q must not contain line breaks.", name))
+			}
+		}
+	}
+
+	sensitiveNames := make(map[string]string, len(sensitive))
+	for name := range sensitive {
+		sensitiveNames[name] = ""
+	}
+	for name := range plain {
+		if _, ok := findHeader(sensitiveNames, name); ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("consumer_inputs").AtName("headers").AtMapKey(name),
+				"Conflicting header",
+				fmt.Sprintf("The header %q is set in both headers and sensitive_headers.", name),
+			)
+		}
+	}
+}
+
+// secretHeadersConfigValidator warns about headers which look like credentials but are set in the
+// plain headers, whose values are shown in plan output and logs.
+type secretHeadersConfigValidator struct{}
+
+func (v secretHeadersConfigValidator) Description(_ context.Context) string {
+	return "credential headers such as Authorization, *-Key and *-Token should be set in sensitive_headers"
+}
+
+func (v secretHeadersConfigValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v secretHeadersConfigValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var headers types.Map
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("consumer_inputs").AtName("headers"), &headers)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for name := range headers.Elements() {
+		if isSecretHeader(name) {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("consumer_inputs").AtName("headers").AtMapKey(name),
+				"Secret header in plain headers",
+				fmt.Sprintf("The header %q looks like a credential, but headers are shown in plan output and logs. Move it to sensitive_headers.", name),
+			)
+		}
+	}
+}
+
+// publisherNamesConfigValidator rejects publisher inputs referencing a project or repository both by
+// name and ID, and repository names without a project to resolve them in.
+type publisherNamesConfigValidator struct{}
+
+func (v publisherNamesConfigValidator) Description(_ context.Context) string {
+	return "publisher_inputs.project_name conflicts with project_id, repository_name conflicts with repository and requires project_id or project_name"
+}
+
+func (v publisherNamesConfigValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v publisherNamesConfigValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var publisherInputs types.Object
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("publisher_inputs"), &publisherInputs)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	inputs := publisherInputsFrom(ctx, publisherInputs, &resp.Diagnostics)
+	if inputs == nil || resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !inputs.ProjectName.IsNull() && !inputs.ProjectId.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("publisher_inputs").AtName("project_name"),
+			"Conflicting project",
+			"Set either publisher_inputs.project_id or publisher_inputs.project_name, not both.",
+		)
+	}
+	if !inputs.RepositoryName.IsNull() && !inputs.RepositoryId.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("publisher_inputs").AtName("repository_name"),
+			"Conflicting repository",
+			"Set either publisher_inputs.repository or publisher_inputs.repository_name, not both.",
+		)
+	}
+	if !inputs.RepositoryName.IsNull() && inputs.ProjectName.IsNull() && inputs.ProjectId.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("publisher_inputs").AtName("repository_name"),
+			"Missing project",
+			"Repository names are resolved within a project, set publisher_inputs.project_id or publisher_inputs.project_name as well.",
+		)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestSuggest(t *testing.T) {
+	cases := map[string]string{
+		"git.psuh":    "git.push",
+		"webhooks":    "webHooks",
+		"httpRequst":  "httpRequest",
+		"custom.evnt": "",
+	}
+
+	for value, expected := range cases {
+		candidates := append(allKnownEventTypes(), knownConsumerIds()...)
+		candidates = append(candidates, allKnownConsumerActions()...)
+		if actual := suggest(value, candidates); actual != expected {
+			t.Errorf("suggest(%q) = %q, expected %q", value, actual, expected)
+		}
+	}
+}
+
+func TestKnownValueValidator(t *testing.T) {
+	cases := []struct {
+		validator validator.String
+		value     string
+		severity  diag.Severity
+	}{
+		{eventTypeValidator(), "git.push", 0},
+		{eventTypeValidator(), "git.psuh", diag.SeverityWarning},
+		{eventTypeValidator(), "myextension.custom-event", diag.SeverityWarning},
+		{messagesValidator(), "markdown", 0},
+		{messagesValidator(), "everything", diag.SeverityError},
+		{messagesValidator(), "markdwn", diag.SeverityError},
+	}
+
+	for _, c := range cases {
+		req := validator.StringRequest{Path: path.Root("test"), ConfigValue: types.StringValue(c.value)}
+		resp := &validator.StringResponse{}
+		c.validator.ValidateString(context.Background(), req, resp)
+
+		switch {
+		case c.severity == 0 && len(resp.Diagnostics) != 0:
+			t.Errorf("%q: expected no diagnostics, got %v", c.value, resp.Diagnostics)
+		case c.severity != 0 && (len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Severity() != c.severity):
+			t.Errorf("%q: expected one diagnostic of severity %v, got %v", c.value, c.severity, resp.Diagnostics)
+		}
+	}
+}
+
+func TestIsSecretHeader(t *testing.T) {
+	cases := map[string]bool{
+		"Authorization":       true,
+		"proxy-authorization": true,
+		"X-Api-Key":           true,
+		"X-Auth-Token":        true,
+		"X-Client-Secret":     true,
+		"X-Trigger":           false,
+		"X-Keyboard":          false,
+		"Content-Type":        false,
+	}
+
+	for name, expected := range cases {
+		if actual := isSecretHeader(name); actual != expected {
+			t.Errorf("isSecretHeader(%q) = %t, expected %t", name, actual, expected)
+		}
+	}
+}
+
+// configWithUnknownInputs returns a web hook configuration whose input blocks are unknown as a whole,
+// e.g. because they are set with a condition which is only known on apply.
+func configWithUnknownInputs(t *testing.T) tfsdk.Config {
+	s := subscriptionSchema(t)
+	objectType := s.Type().TerraformType(context.Background()).(tftypes.Object)
+
+	values := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		values[name] = tftypes.NewValue(attributeType, nil)
+	}
+	for _, name := range []string{"consumer_inputs", "publisher_inputs"} {
+		values[name] = tftypes.NewValue(objectType.AttributeTypes[name], tftypes.UnknownValue)
+	}
+	values["consumer_action_id"] = tftypes.NewValue(tftypes.String, "httpRequest")
+	values["consumer_id"] = tftypes.NewValue(tftypes.String, "webHooks")
+	values["event_type"] = tftypes.NewValue(tftypes.String, "git.push")
+	values["publisher_id"] = tftypes.NewValue(tftypes.String, "tfs")
+
+	return tfsdk.Config{Schema: s, Raw: tftypes.NewValue(objectType, values)}
+}
+
+func TestConfigValidatorsUnknownInputs(t *testing.T) {
+	ctx := context.Background()
+	req := resource.ValidateConfigRequest{Config: configWithUnknownInputs(t)}
+
+	for _, v := range (&SubscriptionResource{}).ConfigValidators(ctx) {
+		resp := &resource.ValidateConfigResponse{}
+		v.ValidateResource(ctx, req, resp)
+		if len(resp.Diagnostics) != 0 {
+			t.Errorf("%T: expected no diagnostics for unknown inputs, got %v", v, resp.Diagnostics)
+		}
+	}
+}